@@ -31,20 +31,48 @@ func init() {
 	zapCfg := zap.NewDevelopmentConfig()
 	zapCfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	zapCfg.Level.SetLevel(zap.WarnLevel)
+	atomicLevel = zapCfg.Level
 	l, err := zapCfg.Build()
 	if err != nil {
 		log.Fatalln("Failed to init zap global logger, no zap log will be shown till zap is properly initialized: ", err)
 	}
 	zap.ReplaceGlobals(l)
 
+	RootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "log level, debug|info|warn|error")
+
 	RootCmd.AddCommand(cmd.ConvertCmd)
+	RootCmd.AddCommand(cmd.DecodeCmd)
 	RootCmd.AddCommand(cmd.ExportCmd)
+	RootCmd.AddCommand(cmd.DumpCmd)
+	RootCmd.AddCommand(cmd.SignCmd)
+	RootCmd.AddCommand(cmd.BroadcastCmd)
+	RootCmd.AddCommand(cmd.MultisendCmd)
+	RootCmd.AddCommand(cmd.VerifyPayoutCmd)
+	RootCmd.AddCommand(cmd.VerifyProofCmd)
+	RootCmd.AddCommand(cmd.APYCmd)
+	RootCmd.AddCommand(cmd.SimulateCmd)
+	RootCmd.AddCommand(cmd.ConfigCmd)
 }
 
+var (
+	// atomicLevel is the level of zap global logger
+	atomicLevel zap.AtomicLevel
+	logLevel    string
+)
+
+// RootCmd is the root command of bookkeeper
 var RootCmd = &cobra.Command{
 	Use:   "bookkeeper",
 	Short: "tool to export rewards",
 	Long:  "bookkeeper is a command line based tool to export rewards by block producer name",
+	PersistentPreRunE: func(c *cobra.Command, args []string) error {
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+			return err
+		}
+		atomicLevel.SetLevel(level)
+		return cmd.ApplyNetwork(c.Flags())
+	},
 }
 
 func main() {
@@ -0,0 +1,179 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var lockDays []uint
+
+// blockInterval is the time between two blocks
+const blockInterval = 10 * time.Second
+
+// apyColumns are the columns of apy estimation
+var apyColumns = []string{
+	"delegate",
+	"epochs",
+	"reward",
+	"votes",
+	"lockDays",
+	"weight",
+	"apy",
+}
+
+// DelegateReturn is the average distributed reward and votes of a delegate over epochs
+type DelegateReturn struct {
+	Epochs int
+	// Reward is the average distributed reward per epoch
+	Reward *big.Int
+	// Votes is the average total votes per epoch in which the delegate has votes
+	Votes *big.Int
+	// RewardPerVote is the average distributed reward per weighted vote per epoch
+	RewardPerVote *big.Rat
+}
+
+// APYCmd estimates the annualized return of voting for delegates
+var APYCmd = &cobra.Command{
+	Use:   "apy bp-name...",
+	Short: "Estimate the annualized return of voting for delegates",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		writer, err := newTableWriter(os.Stdout, outputFormat, apyColumns, columns)
+		if err != nil {
+			return err
+		}
+		return estimateAPY(configPath, endpoint, args, start, to, percentage, withFoundationBonus, lockDays, writer)
+	},
+}
+
+func init() {
+	addConfigFlag(APYCmd.Flags())
+	addEndpointFlags(APYCmd.Flags())
+	addSnapshotFlags(APYCmd.Flags())
+	APYCmd.Flags().Uint64Var(&start, "start", 0, "start epoch number")
+	APYCmd.Flags().Uint64Var(&to, "to", 0, "to epoch number")
+	APYCmd.Flags().UintVarP(&percentage, "percentage", "p", 100, "percentage")
+	APYCmd.Flags().BoolVarP(&withFoundationBonus, "with-foundation-bonus", "w", false, "epoch bonus with foundation bonus")
+	APYCmd.Flags().UintSliceVar(&lockDays, "lock-days", []uint{0, 7, 30, 91, 182, 365}, "lock durations in days to estimate")
+	APYCmd.Flags().StringVarP(&outputFormat, "format", "f", formatCSV, "output format, csv|json|ndjson")
+	APYCmd.Flags().StringSliceVar(&columns, "columns", nil, "comma separated columns to output, all if not specified")
+}
+
+func estimateAPY(
+	configPath string,
+	endpoint string,
+	bps []string,
+	startEpoch uint64,
+	toEpoch uint64,
+	distPercentage uint,
+	withFoundationBonus bool,
+	lockDays []uint,
+	writer *tableWriter,
+) error {
+	if startEpoch == 0 || toEpoch < startEpoch {
+		return errors.Errorf("invalid epoch number from %d and to %d", startEpoch, toEpoch)
+	}
+	committee, err := newCommittee(configPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create committee")
+	}
+	for _, bp := range bps {
+		delegateName, err := decodeDelegateName(bp)
+		if err != nil {
+			return errors.Errorf("failed to parse bp name %s", bp)
+		}
+		ret, err := delegateReturn(committee, endpoint, delegateName, startEpoch, toEpoch, distPercentage, withFoundationBonus)
+		if err != nil {
+			return errors.Wrapf(err, "failed to estimate return of %s", bp)
+		}
+		for _, days := range lockDays {
+			weight := util.VoteWeight(time.Duration(days) * day)
+			// apy in percentage is reward per vote * weight * epochs per year * 100
+			apy := new(big.Rat).Mul(ret.RewardPerVote, new(big.Rat).SetFloat64(weight))
+			apy.Mul(apy, epochsPerYear(numDelegates, numSubEpochs, blockInterval))
+			apy.Mul(apy, big.NewRat(100, 1))
+			if err := writer.Write(
+				delegateNameString(delegateName),
+				ret.Epochs,
+				ret.Reward,
+				ret.Votes,
+				days,
+				new(big.Rat).SetFloat64(weight).FloatString(4),
+				apy.FloatString(4),
+			); err != nil {
+				return errors.Wrap(err, "error writing apy")
+			}
+		}
+	}
+	return writer.Close()
+}
+
+// epochsPerYear returns the number of epochs in a year, as an epoch is numDelegates * numSubEpochs blocks of
+// blockInterval
+func epochsPerYear(numDelegates uint64, numSubEpochs uint64, blockInterval time.Duration) *big.Rat {
+	epoch := time.Duration(numDelegates*numSubEpochs) * blockInterval
+	return big.NewRat(int64(365*day), int64(epoch))
+}
+
+// delegateReturn averages the distributed reward of a delegate over epochs, and its votes over the epochs it has
+// votes in. Epochs in which the delegate has no reward address or votes distribute nothing, so they are counted as
+// epochs of zero reward.
+func delegateReturn(
+	committee util.ResultFetcher,
+	endpoint string,
+	delegateName []byte,
+	startEpoch uint64,
+	toEpoch uint64,
+	distPercentage uint,
+	withFoundationBonus bool,
+) (*DelegateReturn, error) {
+	ret := &DelegateReturn{
+		Reward:        big.NewInt(0),
+		Votes:         big.NewInt(0),
+		RewardPerVote: new(big.Rat),
+	}
+	var votedEpochs int64
+	for epochNum := startEpoch; epochNum <= toEpoch; epochNum++ {
+		height, err := gravityChainHeight(endpoint, epochNum)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get gravity chain height for epoch %d", epochNum)
+		}
+		rewardAddress, totalVotes, _, err := readEthereum(height, delegateName, committee)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch data from ethereum for epoch %d", epochNum)
+		}
+		ret.Epochs++
+		if len(rewardAddress) == 0 || totalVotes.Sign() == 0 {
+			continue
+		}
+		reward, err := getReward(endpoint, epochNum, rewardAddress, withFoundationBonus)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch reward for epoch %d", epochNum)
+		}
+		reward = new(big.Int).Div(new(big.Int).Mul(reward, new(big.Int).SetUint64(uint64(distPercentage))), big.NewInt(100))
+		votedEpochs++
+		ret.Reward.Add(ret.Reward, reward)
+		ret.Votes.Add(ret.Votes, totalVotes)
+		ret.RewardPerVote.Add(ret.RewardPerVote, new(big.Rat).SetFrac(reward, totalVotes))
+	}
+	if votedEpochs == 0 {
+		return ret, nil
+	}
+	epochs := big.NewInt(int64(ret.Epochs))
+	ret.Reward.Div(ret.Reward, epochs)
+	ret.Votes.Div(ret.Votes, big.NewInt(votedEpochs))
+	ret.RewardPerVote.Quo(ret.RewardPerVote, new(big.Rat).SetInt(epochs))
+	return ret, nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestEpochsPerYear(t *testing.T) {
+	for _, test := range []struct {
+		numDelegates  uint64
+		numSubEpochs  uint64
+		blockInterval time.Duration
+		epochs        string
+	}{
+		// one-hour epochs of mainnet
+		{24, 15, 10 * time.Second, "8760"},
+		{36, 30, 5 * time.Second, "5840"},
+		{24, 15, 5 * time.Second, "17520"},
+		{1, 1, 7 * time.Second, "31536000/7"},
+	} {
+		epochs := epochsPerYear(test.numDelegates, test.numSubEpochs, test.blockInterval)
+		if epochs.RatString() != test.epochs {
+			t.Errorf(
+				"expecting %s epochs per year of %d delegates, %d sub epochs and %s blocks, but got %s",
+				test.epochs,
+				test.numDelegates,
+				test.numSubEpochs,
+				test.blockInterval,
+				epochs.RatString(),
+			)
+		}
+	}
+}
+
+func TestDelegateReturn(t *testing.T) {
+	_, addr, stop := startFakeChain(t)
+	defer stop()
+	committee := newFakeCommittee(t)
+	for _, test := range []struct {
+		startEpoch    uint64
+		toEpoch       uint64
+		epochs        int
+		reward        *big.Int
+		votes         *big.Int
+		rewardPerVote *big.Rat
+	}{
+		// 200 IOTX for 3000 IOTX of votes in epoch 1, and for 2300 IOTX of votes in epoch 2
+		{1, 2, 2, iotx(200), iotx(2650), new(big.Rat).Add(big.NewRat(1, 30), big.NewRat(1, 23))},
+		// iotexlab has no reward address in epoch 7, which is an epoch of zero reward
+		{7, 8, 2, iotx(100), iotx(3000), big.NewRat(1, 30)},
+		{7, 7, 1, big.NewInt(0), big.NewInt(0), new(big.Rat)},
+	} {
+		ret, err := delegateReturn(committee, addr, delegateName(t, "iotexlab"), test.startEpoch, test.toEpoch, 100, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ret.Epochs != test.epochs ||
+			ret.Reward.Cmp(test.reward) != 0 ||
+			ret.Votes.Cmp(test.votes) != 0 ||
+			ret.RewardPerVote.Cmp(test.rewardPerVote) != 0 {
+			t.Errorf(
+				"expecting return of epoch %d to %d to be %d epochs, %d reward, %d votes and %s per vote, but got %+v",
+				test.startEpoch,
+				test.toEpoch,
+				test.epochs,
+				test.reward,
+				test.votes,
+				test.rewardPerVote.RatString(),
+				ret,
+			)
+		}
+	}
+	if _, err := delegateReturn(committee, addr, delegateName(t, "iotexlab"), 2, 3, 100, false); err == nil {
+		t.Error("expecting error of epoch 3 without grant reward")
+	}
+}
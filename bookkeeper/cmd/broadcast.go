@@ -0,0 +1,61 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iotexproject/iotex-core/protogen/iotexapi"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// BroadcastCmd submits a signed action
+var BroadcastCmd = &cobra.Command{
+	Use:   "broadcast signed-file",
+	Short: "Broadcast a signed action to iotex chain",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return broadcast(args[0], endpoint)
+	},
+}
+
+func init() {
+	addEndpointFlags(BroadcastCmd.Flags())
+}
+
+func broadcast(signedFile string, endpoint string) error {
+	var signed SignedAction
+	if err := readJSON(signedFile, &signed); err != nil {
+		return err
+	}
+	act, err := signed.Verify()
+	if err != nil {
+		return errors.Wrapf(err, "invalid signed action %s", signedFile)
+	}
+	conn, err := dial(endpoint)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	cli := iotexapi.NewAPIServiceClient(conn)
+	ctx := context.Background()
+	accountResponse, err := cli.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: signed.Sender})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get account %s", signed.Sender)
+	}
+	if pendingNonce := accountResponse.AccountMeta.PendingNonce; pendingNonce != signed.Envelope.Nonce {
+		return errors.Errorf("nonce %d of action does not match pending nonce %d of %s", signed.Envelope.Nonce, pendingNonce, signed.Sender)
+	}
+	if _, err := cli.SendAction(ctx, &iotexapi.SendActionRequest{Action: act}); err != nil {
+		return errors.Wrap(err, "failed to send action")
+	}
+	fmt.Printf("Action %s has been broadcast\n", signed.Hash)
+	return nil
+}
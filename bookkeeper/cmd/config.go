@@ -0,0 +1,133 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/iotexproject/iotex-election/committee"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	gravityAPIs        []string
+	gravityStartHeight uint64
+	registerContract   string
+	stakingContract    string
+	gravityRate        float64
+	showSecrets        bool
+)
+
+// ConfigCmd shows and validates the committee config
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or validate the committee config",
+}
+
+// ConfigShowCmd prints the committee config after overrides
+var ConfigShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the committee config after environment variable and flag overrides",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+		if !showSecrets {
+			apis := make([]string, len(config.GravityChainAPIs))
+			for i, api := range config.GravityChainAPIs {
+				apis[i] = util.MaskURL(api)
+			}
+			config.GravityChainAPIs = apis
+		}
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal config")
+		}
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+// ConfigValidateCmd validates the committee config after overrides
+var ConfigValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the committee config after environment variable and flag overrides",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return err
+		}
+		if err := util.ValidateCommitteeConfig(config); err != nil {
+			return err
+		}
+		fmt.Println("config is valid")
+		return nil
+	},
+}
+
+func init() {
+	addConfigFlag(ConfigCmd.PersistentFlags())
+	addNetworkFlags(ConfigCmd.PersistentFlags())
+	ConfigCmd.AddCommand(ConfigShowCmd)
+	ConfigCmd.AddCommand(ConfigValidateCmd)
+	ConfigShowCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "show gravity chain apis without masking api keys")
+}
+
+// addConfigFlag adds the flag of committee config file and the flags overriding it
+func addConfigFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&configPath, "config", "committee.yaml", "config file")
+	flags.StringSliceVar(&gravityAPIs, "gravity-api", nil, "gravity chain apis overriding config")
+	flags.Uint64Var(&gravityStartHeight, "gravity-start-height", 0, "gravity chain start height overriding config")
+	flags.StringVar(&registerContract, "register-contract", "", "register contract address overriding config")
+	flags.StringVar(&stakingContract, "staking-contract", "", "staking contract address overriding config")
+	flags.Float64Var(&gravityRate, "gravity-rate", 0, "max fetches per second from each gravity chain api, no limit if 0")
+}
+
+// loadConfig loads the committee config from file, overridden by environment variables and then flags
+func loadConfig(configPath string) (committee.Config, error) {
+	config, err := util.LoadCommitteeConfig(configPath)
+	if err != nil {
+		return config, err
+	}
+	applyConfigFlags(&config)
+	return config, nil
+}
+
+// applyConfigFlags overrides the committee config with the flags given
+func applyConfigFlags(config *committee.Config) {
+	if len(gravityAPIs) != 0 {
+		config.GravityChainAPIs = gravityAPIs
+	}
+	if gravityStartHeight != 0 {
+		config.GravityChainStartHeight = gravityStartHeight
+	}
+	if registerContract != "" {
+		config.RegisterContractAddress = registerContract
+	}
+	if stakingContract != "" {
+		config.StakingContractAddress = stakingContract
+	}
+}
+
+// newLiveCommittee creates a committee fetching results from the healthiest gravity chain api, failing over to
+// the others
+func newLiveCommittee(configPath string) (util.ResultFetcher, error) {
+	return util.NewCommitteeWithConfigFile(
+		configPath,
+		util.WithConfigOverride(applyConfigFlags),
+		util.WithRateLimit(gravityRate),
+	)
+}
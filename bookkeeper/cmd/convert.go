@@ -7,17 +7,19 @@
 package cmd
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/hex"
 	"fmt"
 	"io"
-	"log"
 	"math/big"
 	"os"
 	"strings"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/protogen/iotexapi"
+	"github.com/iotexproject/iotex-tools/util"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -275,13 +277,47 @@ var ConvertCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
-		total, bytecode, err := convertToBytecode(args[0], inputUnit, msg)
-		if err == nil {
-			totalInFloat := new(big.Float).SetInt(total)
-			fmt.Printf("Total Amount: %.18f IOTX or %d Rau\n", totalInFloat.Quo(totalInFloat, OneIOTX), total)
-			fmt.Printf("Byte Code: %s\n", hex.EncodeToString(bytecode))
+		mode, err := util.ParseRoundingMode(rounding)
+		if err != nil {
+			return err
 		}
-		return err
+		records, err := readRecords(args[0], inputUnit, mode)
+		if err != nil {
+			return err
+		}
+		if err := screenRecords(records, checkZeroAddress, contractListFile, denyListFile); err != nil {
+			return err
+		}
+		if records, err = dedupRecords(records, mergeDuplicates); err != nil {
+			return err
+		}
+		reportRounding(records)
+		profile, err := loadProfile(abiFile, methodName, methodArgs)
+		if err != nil {
+			return err
+		}
+		total, bytecode, err := convertToBytecode(profile, records, msg)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Total Amount: %s IOTX or %d Rau\n", util.FormatIOTX(total), total)
+		fmt.Printf("Byte Code: %s\n", hex.EncodeToString(bytecode))
+		if merkleFile != "" {
+			if err := writeMerkleDistribution(records, merkleFile); err != nil {
+				return err
+			}
+		}
+		if envelopeFile == "" {
+			return nil
+		}
+		if !profile.Builtin {
+			return errors.New("envelope is only supported for the built-in multisend contract")
+		}
+		tipsInRau, err := parseRau("tips", tips)
+		if err != nil {
+			return err
+		}
+		return writeEnvelope(bytecode, total, tipsInRau)
 	},
 }
 
@@ -290,66 +326,223 @@ var (
 	inputUnit  string
 	format     string
 	msg        string
+	rounding   string
+
+	envelopeFile string
+	contract     string
+	sender       string
+	nonce        uint64
+	gasLimit     uint64
+	gasPrice     string
+	tips         string
 )
 
+// Record is a row of payout csv
+type Record struct {
+	Line    int
+	Address common.Address
+	Amount  *big.Int
+	// Exact is the amount in Rau as written in csv, before rounding
+	Exact *big.Rat
+	// Fields are all columns of the row
+	Fields []string
+}
+
 func init() {
 	ConvertCmd.Flags().StringVar(&inputUnit, "input-unit", "Rau", "output file")
 	ConvertCmd.Flags().StringVar(&msg, "msg", "", "message to append")
+	ConvertCmd.Flags().StringVar(&rounding, "rounding", "none", "rounding mode of amounts finer than 1 Rau, none|down|up|half-up|half-even")
+	ConvertCmd.Flags().StringVar(&envelopeFile, "envelope", "", "output file of unsigned execution envelope")
+	ConvertCmd.Flags().StringVar(&contract, "contract", "", "multisend contract address")
+	ConvertCmd.Flags().StringVar(&sender, "sender", "", "payout address to read pending nonce from")
+	ConvertCmd.Flags().Uint64Var(&nonce, "nonce", 0, "nonce of execution, read from chain if not specified")
+	ConvertCmd.Flags().Uint64Var(&gasLimit, "gas-limit", 7000000, "gas limit of execution")
+	ConvertCmd.Flags().StringVar(&gasPrice, "gas-price", "1000000000000", "gas price of execution in Rau")
+	ConvertCmd.Flags().StringVar(&tips, "tips", "0", "tips to multisend contract in Rau")
+	addEndpointFlags(ConvertCmd.Flags())
+}
+
+func writeEnvelope(bytecode []byte, total *big.Int, tipsInRau *big.Int) error {
+	envelope, err := newEnvelope(bytecode, total, tipsInRau)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(envelopeFile, envelope); err != nil {
+		return err
+	}
+	fmt.Printf("Unsigned envelope has been written to %s\n", envelopeFile)
+	return nil
+}
+
+func newEnvelope(bytecode []byte, total *big.Int, tipsInRau *big.Int) (*Envelope, error) {
+	contractAddr, err := util.ParseAddress(contract)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid contract address")
+	}
+	contractIoAddr, err := address.FromBytes(contractAddr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if nonce == 0 {
+		if sender == "" {
+			return nil, errors.New("either nonce or sender should be specified")
+		}
+		if nonce, err = pendingNonce(endpoint, sender); err != nil {
+			return nil, err
+		}
+	}
+	value, err := checkEnvelopeData(bytecode, total)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid envelope")
+	}
+	envelope := &Envelope{
+		Contract: contractIoAddr.String(),
+		Amount:   new(big.Int).Add(value, tipsInRau).String(),
+		Total:    total.String(),
+		Tips:     tipsInRau.String(),
+		Nonce:    nonce,
+		GasLimit: gasLimit,
+		GasPrice: gasPrice,
+		Data:     hex.EncodeToString(bytecode),
+	}
+	if _, err := envelope.Core(); err != nil {
+		return nil, errors.Wrap(err, "invalid envelope")
+	}
+	return envelope, nil
+}
+
+func pendingNonce(endpoint string, addr string) (uint64, error) {
+	conn, err := dial(endpoint)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	response, err := iotexapi.NewAPIServiceClient(conn).GetAccount(
+		context.Background(),
+		&iotexapi.GetAccountRequest{Address: addr},
+	)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get account %s", addr)
+	}
+	return response.AccountMeta.PendingNonce, nil
+}
+
+func convertToBytecode(profile *PayoutProfile, records []*Record, message string) (totalAmount *big.Int, bytecode []byte, err error) {
+	_, _, totalAmount = splitRecords(records)
+	bytecode, err = profile.Pack(records, message)
+	return
 }
 
-func convertToBytecode(csvFile string, unit string, message string) (totalAmount *big.Int, bytecode []byte, err error) {
+// readRecords reads addresses and amounts from csv file. All rows are validated before returning, and
+// the invalid ones are reported with their line numbers.
+func readRecords(csvFile string, unit string, mode util.RoundingMode) ([]*Record, error) {
+	var decimals int
 	switch strings.ToLower(unit) {
 	case "rau":
-		unit = "Rau"
+		decimals = 0
 	case "iotx":
-		unit = "IOTX"
+		decimals = util.IOTXDecimals
 	default:
-		err = errors.Errorf("invalid unit type %s", unit)
-		return
+		return nil, errors.Errorf("invalid unit type %s", unit)
 	}
 	f, err := os.Open(csvFile)
 	if err != nil {
-		return
+		return nil, err
 	}
 	defer f.Close()
-	totalAmount = big.NewInt(0)
-	var addrs []common.Address
-	var amounts []*big.Int
 	reader := csv.NewReader(f)
-	var record []string
-	for {
-		record, err = reader.Read()
+	reader.FieldsPerRecord = -1
+	var records []*Record
+	var invalidRows []string
+	for line := 1; ; line++ {
+		row, err := reader.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return
+			return nil, err
 		}
-		addrs = append(addrs, common.HexToAddress(record[0]))
-		amount, ok := new(big.Float).SetString(record[1])
-		if !ok {
-			err = errors.Errorf("failed to parse record %s", record[1])
-			return
-		}
-		if amount.Sign() != 1 {
-			err = errors.Errorf("amount %f is not a positive value", amount)
-			return
-		}
-		if unit == "IOTX" {
-			amount = amount.Mul(amount, OneIOTX)
+		record, err := parseRecord(row, decimals, mode)
+		if err != nil {
+			invalidRows = append(invalidRows, fmt.Sprintf("line %d: %v", line, err))
+			continue
 		}
-		amountInInt, _ := amount.Int(nil)
-		totalAmount = totalAmount.Add(totalAmount, amountInInt)
-		amounts = append(amounts, amountInInt)
+		record.Line = line
+		records = append(records, record)
 	}
-	if len(amounts) == 0 {
-		err = errors.Errorf("no records in csv file %s", csvFile)
-		return
+	if len(invalidRows) != 0 {
+		return nil, errors.Errorf(
+			"%d invalid records in csv file %s\n%s",
+			len(invalidRows),
+			csvFile,
+			strings.Join(invalidRows, "\n"),
+		)
 	}
-	multisendABI, err := abi.JSON(strings.NewReader(MultisendABI))
+	if len(records) == 0 {
+		return nil, errors.Errorf("no records in csv file %s", csvFile)
+	}
+	return records, nil
+}
+
+func parseRecord(row []string, decimals int, mode util.RoundingMode) (*Record, error) {
+	if len(row) < 2 {
+		return nil, errors.Errorf("expecting address and amount, but got %d fields", len(row))
+	}
+	addr, err := util.ParseAddress(row[0])
 	if err != nil {
-		log.Fatalf("invalid abi %s\n", MultisendABI)
+		return nil, err
+	}
+	amount, exact, err := util.ParseAmount(row[1], decimals, mode)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() != 1 {
+		return nil, errors.Errorf("amount %s is not a positive value", row[1])
+	}
+	return &Record{Address: addr, Amount: amount, Exact: exact, Fields: row}, nil
+}
+
+func splitRecords(records []*Record) (addrs []common.Address, amounts []*big.Int, totalAmount *big.Int) {
+	totalAmount = big.NewInt(0)
+	for _, record := range records {
+		addrs = append(addrs, record.Address)
+		amounts = append(amounts, record.Amount)
+		totalAmount.Add(totalAmount, record.Amount)
 	}
-	bytecode, err = multisendABI.Pack(sendCoin, addrs, amounts, message)
 	return
 }
+
+// reportRounding prints the rows changed by rounding, and how the total amount is changed
+func reportRounding(records []*Record) {
+	exactTotal := new(big.Rat)
+	roundedTotal := new(big.Rat)
+	var rounded int
+	for _, record := range records {
+		amount := new(big.Rat).SetInt(record.Amount)
+		exactTotal.Add(exactTotal, record.Exact)
+		roundedTotal.Add(roundedTotal, amount)
+		if amount.Cmp(record.Exact) == 0 {
+			continue
+		}
+		if rounded == 0 {
+			fmt.Println("Rounding report:")
+		}
+		rounded++
+		fmt.Printf(
+			"\tline %d: %s Rau rounded to %d Rau\n",
+			record.Line,
+			record.Exact.FloatString(util.IOTXDecimals),
+			record.Amount,
+		)
+	}
+	if rounded == 0 {
+		return
+	}
+	fmt.Printf(
+		"\t%d amounts rounded, total changed from %s Rau to %s Rau by %s Rau\n",
+		rounded,
+		exactTotal.FloatString(util.IOTXDecimals),
+		roundedTotal.FloatString(0),
+		new(big.Rat).Sub(roundedTotal, exactTotal).FloatString(util.IOTXDecimals),
+	)
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/protogen/iotexapi"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// sendToken is the api name to call for token distribution
+const sendToken = "sendToken"
+
+var (
+	expectedCSV string
+	isHash      bool
+)
+
+// Payout is a multisend call decoded from bytecode
+type Payout struct {
+	Method     string
+	Token      common.Address
+	Recipients []common.Address
+	Amounts    []*big.Int
+	Payload    string
+}
+
+// Total returns the sum of all amounts in the payout
+func (p *Payout) Total() *big.Int {
+	total := big.NewInt(0)
+	for _, amount := range p.Amounts {
+		total.Add(total, amount)
+	}
+	return total
+}
+
+// DecodeCmd decodes multisend bytecode into a payout list
+var DecodeCmd = &cobra.Command{
+	Use:   "decode bytecode|--hash action-hash",
+	Short: "Decode multisend bytecode or action into a payout list",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		bytecode, err := fetchBytecode(args[0], isHash, endpoint)
+		if err != nil {
+			return err
+		}
+		payout, err := decodeBytecode(bytecode)
+		if err != nil {
+			return err
+		}
+		if err := printPayout(payout); err != nil {
+			return err
+		}
+		if expectedCSV == "" {
+			return nil
+		}
+		mode, err := util.ParseRoundingMode(rounding)
+		if err != nil {
+			return err
+		}
+		return diffPayout(payout, expectedCSV, inputUnit, mode)
+	},
+}
+
+func init() {
+	addEndpointFlags(DecodeCmd.Flags())
+	DecodeCmd.Flags().BoolVar(&isHash, "hash", false, "decode the execution of the action of the hash rather than bytecode")
+	DecodeCmd.Flags().StringVar(&expectedCSV, "expect", "", "csv file the payout should match")
+	DecodeCmd.Flags().StringVar(&inputUnit, "input-unit", "Rau", "unit of amount in expected csv")
+	DecodeCmd.Flags().StringVar(&rounding, "rounding", "none", "rounding mode of amounts in expected csv finer than 1 Rau")
+}
+
+// fetchBytecode returns the input as bytecode, or reads the execution data of the action if input is a hash
+func fetchBytecode(input string, isHash bool, endpoint string) ([]byte, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s", input)
+	}
+	if !isHash {
+		return data, nil
+	}
+	if len(data) != len(common.Hash{}) {
+		return nil, errors.Errorf("invalid action hash %s", input)
+	}
+	conn, err := dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	request := &iotexapi.GetActionsRequest{
+		Lookup: &iotexapi.GetActionsRequest_ByHash{
+			ByHash: &iotexapi.GetActionByHashRequest{
+				ActionHash: hex.EncodeToString(data),
+			},
+		},
+	}
+	response, err := iotexapi.NewAPIServiceClient(conn).GetActions(context.Background(), request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get action %s", input)
+	}
+	if len(response.ActionInfo) == 0 {
+		return nil, errors.Errorf("action %s not found", input)
+	}
+	execution := response.ActionInfo[0].Action.Core.GetExecution()
+	if execution == nil {
+		return nil, errors.Errorf("action %s is not an execution", input)
+	}
+	return execution.Data, nil
+}
+
+func decodeBytecode(bytecode []byte) (*Payout, error) {
+	if len(bytecode) < 4 {
+		return nil, errors.Errorf("bytecode %x is too short", bytecode)
+	}
+	multisendABI, err := abi.JSON(strings.NewReader(MultisendABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid multisend abi")
+	}
+	method, err := multisendABI.MethodById(bytecode[:4])
+	if err != nil {
+		return nil, errors.Wrapf(err, "unknown method %x", bytecode[:4])
+	}
+	values, err := method.Inputs.UnpackValues(bytecode[4:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unpack arguments of %s", method.Name)
+	}
+	payout := &Payout{Method: method.Name}
+	switch method.Name {
+	case sendCoin:
+	case sendToken:
+		token, ok := values[0].(common.Address)
+		if !ok {
+			return nil, errors.Errorf("invalid token address %v", values[0])
+		}
+		payout.Token = token
+		values = values[1:]
+	default:
+		return nil, errors.Errorf("method %s is not a payout", method.Name)
+	}
+	var ok bool
+	if payout.Recipients, ok = values[0].([]common.Address); !ok {
+		return nil, errors.Errorf("invalid recipients %v", values[0])
+	}
+	if payout.Amounts, ok = values[1].([]*big.Int); !ok {
+		return nil, errors.Errorf("invalid amounts %v", values[1])
+	}
+	if payout.Payload, ok = values[2].(string); !ok {
+		return nil, errors.Errorf("invalid payload %v", values[2])
+	}
+	if len(payout.Recipients) != len(payout.Amounts) {
+		return nil, errors.Errorf(
+			"%d recipients do not match %d amounts",
+			len(payout.Recipients),
+			len(payout.Amounts),
+		)
+	}
+	return payout, nil
+}
+
+func printPayout(payout *Payout) error {
+	fmt.Printf("Method: %s\n", payout.Method)
+	if payout.Method == sendToken {
+		fmt.Printf("Token: %s\n", payout.Token.String())
+	}
+	for i, recipient := range payout.Recipients {
+		ioAddr, err := address.FromBytes(recipient.Bytes())
+		if err != nil {
+			return err
+		}
+		fmt.Printf(
+			"%s %s %d Rau %s IOTX\n",
+			ioAddr.String(),
+			recipient.String(),
+			payout.Amounts[i],
+			util.FormatIOTX(payout.Amounts[i]),
+		)
+	}
+	total := payout.Total()
+	fmt.Printf("Recipients: %d\n", len(payout.Recipients))
+	fmt.Printf("Total Amount: %s IOTX or %d Rau\n", util.FormatIOTX(total), total)
+	fmt.Printf("Payload: %s\n", payout.Payload)
+	return nil
+}
+
+// diffPayout compares the payout with the records in csv file, and returns an error if they differ
+func diffPayout(payout *Payout, csvFile string, unit string, mode util.RoundingMode) error {
+	records, err := readRecords(csvFile, unit, mode)
+	if err != nil {
+		return err
+	}
+	addrs, amounts, _ := splitRecords(records)
+	if diffs := compareAmounts(addrs, amounts, payout.Recipients, payout.Amounts, "decoded"); diffs != 0 {
+		return errors.Errorf("payout differs from %s in %d entries", csvFile, diffs)
+	}
+	fmt.Printf("payout matches %s\n", csvFile)
+	return nil
+}
+
+// compareAmounts prints the recipients which are missing, unexpected or of different amounts in actual
+// payout, and returns the number of them
+func compareAmounts(
+	expectedAddrs []common.Address,
+	expectedAmounts []*big.Int,
+	actualAddrs []common.Address,
+	actualAmounts []*big.Int,
+	label string,
+) int {
+	expected := sumByAddress(expectedAddrs, expectedAmounts)
+	actual := sumByAddress(actualAddrs, actualAmounts)
+	var diffs int
+	for _, addr := range expectedAddrs {
+		amount, ok := expected[addr]
+		if !ok {
+			continue
+		}
+		delete(expected, addr)
+		actualAmount, ok := actual[addr]
+		switch {
+		case !ok:
+			fmt.Printf("missing: %s %d Rau\n", addr.String(), amount)
+			diffs++
+		case actualAmount.Cmp(amount) != 0:
+			fmt.Printf("mismatch: %s expected %d Rau, %s %d Rau\n", addr.String(), amount, label, actualAmount)
+			diffs++
+		}
+		delete(actual, addr)
+	}
+	for _, addr := range actualAddrs {
+		amount, ok := actual[addr]
+		if !ok {
+			continue
+		}
+		delete(actual, addr)
+		fmt.Printf("unexpected: %s %d Rau\n", addr.String(), amount)
+		diffs++
+	}
+	return diffs
+}
+
+func sumByAddress(addrs []common.Address, amounts []*big.Int) map[common.Address]*big.Int {
+	sums := make(map[common.Address]*big.Int)
+	for i, addr := range addrs {
+		if _, ok := sums[addr]; !ok {
+			sums[addr] = big.NewInt(0)
+		}
+		sums[addr].Add(sums[addr], amounts[i])
+	}
+	return sums
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-tools/util"
+)
+
+const tokenAddress = "0x1111111111111111111111111111111111111111"
+
+// payoutRecords returns the records paying 1 IOTX to A and 2 IOTX to B
+func payoutRecords() []*Record {
+	return []*Record{
+		{Line: 1, Address: common.HexToAddress(voterA), Amount: iotx(1)},
+		{Line: 2, Address: common.HexToAddress(voterB), Amount: iotx(2)},
+	}
+}
+
+func packPayout(t *testing.T, method string, args string) []byte {
+	t.Helper()
+	profile, err := loadProfile("", method, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, bytecode, err := convertToBytecode(profile, payoutRecords(), "epoch 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytecode
+}
+
+func TestFetchBytecode(t *testing.T) {
+	// 32 bytes of calldata are not mistaken for an action hash
+	raw := "0x" + strings.Repeat("ab", 32)
+	data, err := fetchBytecode(raw, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 32 || data[0] != 0xab {
+		t.Errorf("expecting 32 bytes of bytecode, but got %x", data)
+	}
+	if _, err := fetchBytecode("0xabcd", true, ""); err == nil {
+		t.Error("expecting error of action hash shorter than 32 bytes")
+	}
+	if _, err := fetchBytecode("0xxyz", false, ""); err == nil {
+		t.Error("expecting error of invalid hex")
+	}
+}
+
+func TestDecodeBytecode(t *testing.T) {
+	for _, test := range []struct {
+		method string
+		args   string
+		token  common.Address
+	}{
+		{sendCoin, "address,amount,msg", common.Address{}},
+		{sendToken, "const:" + tokenAddress + ",address,amount,msg", common.HexToAddress(tokenAddress)},
+	} {
+		payout, err := decodeBytecode(packPayout(t, test.method, test.args))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if payout.Method != test.method || payout.Token != test.token || payout.Payload != "epoch 1" {
+			t.Errorf("unexpected payout %+v of %s", payout, test.method)
+		}
+		records := payoutRecords()
+		if len(payout.Recipients) != len(records) {
+			t.Fatalf("expecting %d recipients, but got %d", len(records), len(payout.Recipients))
+		}
+		for i, record := range records {
+			if payout.Recipients[i] != record.Address || payout.Amounts[i].Cmp(record.Amount) != 0 {
+				t.Errorf("expecting %s %d, but got %s %d", record.Address.Hex(), record.Amount, payout.Recipients[i].Hex(), payout.Amounts[i])
+			}
+		}
+		if payout.Total().Cmp(iotx(3)) != 0 {
+			t.Errorf("expecting total %d, but got %d", iotx(3), payout.Total())
+		}
+	}
+}
+
+func TestDecodeBytecodeError(t *testing.T) {
+	multisendABI, err := abi.JSON(strings.NewReader(MultisendABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setLimit, err := multisendABI.Pack("setLimit", big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := packPayout(t, sendCoin, "address,amount,msg")
+	for _, test := range []struct {
+		bytecode []byte
+		reason   string
+	}{
+		{[]byte{0x01, 0x02}, "too short"},
+		{[]byte{0x01, 0x02, 0x03, 0x04}, "unknown method"},
+		{setLimit, "not a payout"},
+		{truncated[:len(truncated)-64], "truncated arguments"},
+	} {
+		if _, err := decodeBytecode(test.bytecode); err == nil {
+			t.Errorf("expecting error of bytecode %s", test.reason)
+		}
+	}
+}
+
+func TestDiffPayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "decode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	payout, err := decodeBytecode(packPayout(t, sendCoin, "address,amount,msg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		csv     string
+		matched bool
+	}{
+		{"0x" + voterB + ",2\n0x" + voterA + ",1\n", true},
+		{"0x" + voterA + ",0.5\n0x" + voterA + ",0.5\n0x" + voterB + ",2\n", true},
+		{"0x" + voterA + ",1\n0x" + voterB + ",3\n", false},
+		{"0x" + voterA + ",1\n", false},
+		{"0x" + voterA + ",1\n0x" + voterB + ",2\n0x" + voterC + ",1\n", false},
+	} {
+		csvFile := filepath.Join(dir, "expected.csv")
+		if err := ioutil.WriteFile(csvFile, []byte(test.csv), 0644); err != nil {
+			t.Fatal(err)
+		}
+		err := diffPayout(payout, csvFile, "IOTX", util.RoundNone)
+		if test.matched && err != nil {
+			t.Errorf("expecting payout to match %q, but got %v", test.csv, err)
+		}
+		if !test.matched && err == nil {
+			t.Errorf("expecting payout to differ from %q", test.csv)
+		}
+	}
+}
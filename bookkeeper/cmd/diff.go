@@ -0,0 +1,292 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// changeJoined is the change of a delegate or voter only in the later result
+	changeJoined = "joined"
+	// changeLeft is the change of a delegate or voter only in the earlier result
+	changeLeft = "left"
+	// changeDecayed is the change of a bucket whose votes are less
+	changeDecayed = "decayed"
+	// changeChanged is any other change of tokens or votes
+	changeChanged = "changed"
+	// changeUnchanged is the change of a delegate, or of a voter whose buckets changed, whose tokens and votes are
+	// the same
+	changeUnchanged = "unchanged"
+	// rowDelegate is the type of a row of the totals of a delegate
+	rowDelegate = "delegate"
+	// rowVoter is the type of a row of the totals of a voter's buckets voted to a delegate
+	rowVoter = "voter"
+)
+
+var (
+	fromEpoch  uint64
+	toEpoch    uint64
+	fromHeight uint64
+	toHeight   uint64
+)
+
+// diffColumns are the columns of diff. The voter is empty in the row of a delegate, and the start time is only in
+// the row of a bucket.
+var diffColumns = []string{
+	"delegate",
+	"type",
+	"voter",
+	"startTime",
+	"change",
+	"fromTokens",
+	"toTokens",
+	"fromVotes",
+	"toVotes",
+	"deltaVotes",
+}
+
+// VoteStake is the tokens and votes of a delegate, or of a bucket voted to a delegate
+type VoteStake struct {
+	Tokens *big.Int
+	Votes  *big.Int
+}
+
+// bucketKey identifies a bucket voted to a delegate across results, as a bucket of the same voter and start time
+type bucketKey struct {
+	voter     string
+	startTime string
+}
+
+// DiffCmd compares the votes of two results
+var DiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the votes of delegates and voters between two epochs or heights",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		filter, err := newVoteFilter(delegates, nil, "0")
+		if err != nil {
+			return err
+		}
+		writer, err := newTableWriter(os.Stdout, outputFormat, diffColumns, columns)
+		if err != nil {
+			return err
+		}
+		if (fromEpoch == 0 && fromHeight == 0) || (toEpoch == 0 && toHeight == 0) {
+			return errors.New("both --from-epoch or --from-height and --to-epoch or --to-height are required")
+		}
+		from, err := fetchResult(configPath, endpoint, fromEpoch, fromHeight)
+		if err != nil {
+			return err
+		}
+		to, err := fetchResult(configPath, endpoint, toEpoch, toHeight)
+		if err != nil {
+			return err
+		}
+		return diffResults(from, to, filter, writer)
+	},
+}
+
+func init() {
+	DumpCmd.AddCommand(DiffCmd)
+	DiffCmd.Flags().Uint64Var(&fromEpoch, "from-epoch", 0, "iotex epoch to compare from")
+	DiffCmd.Flags().Uint64Var(&toEpoch, "to-epoch", 0, "iotex epoch to compare to")
+	DiffCmd.Flags().Uint64Var(&fromHeight, "from-height", 0, "ethereum height to compare from")
+	DiffCmd.Flags().Uint64Var(&toHeight, "to-height", 0, "ethereum height to compare to")
+	DiffCmd.Flags().StringSliceVar(&delegates, "delegate", nil, "only compare the delegates")
+}
+
+func diffResults(from *types.ElectionResult, to *types.ElectionResult, filter *voteFilter, writer *tableWriter) error {
+	// delegates are ordered by rank in the later result, followed by the ones which left
+	var names [][]byte
+	for _, delegate := range to.Delegates() {
+		names = append(names, delegate.Name())
+	}
+	for _, delegate := range from.Delegates() {
+		if !containsBytes(names, delegate.Name()) {
+			names = append(names, delegate.Name())
+		}
+	}
+	fromDelegates := delegateStakes(from)
+	toDelegates := delegateStakes(to)
+	for _, name := range names {
+		if !filter.matchDelegate(name) {
+			continue
+		}
+		delegate := delegateNameString(name)
+		if err := writeDiff(writer, delegate, rowDelegate, "", "", fromDelegates[string(name)], toDelegates[string(name)], true); err != nil {
+			return err
+		}
+		if err := diffVoters(writer, delegate, from.VotesByDelegate(name), to.VotesByDelegate(name)); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// diffVoters writes the changes of the voters of a delegate, each followed by the changes of its buckets. Voters and
+// buckets are ordered as in the earlier votes, followed by the new ones.
+func diffVoters(writer *tableWriter, delegate string, fromVotes []*types.Vote, toVotes []*types.Vote) error {
+	fromBuckets, keys := bucketStakes(fromVotes)
+	toBuckets, toKeys := bucketStakes(toVotes)
+	for _, key := range toKeys {
+		if _, ok := fromBuckets[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	var voters []string
+	bucketsOfVoter := make(map[string][]bucketKey)
+	for _, key := range keys {
+		if _, ok := bucketsOfVoter[key.voter]; !ok {
+			voters = append(voters, key.voter)
+		}
+		bucketsOfVoter[key.voter] = append(bucketsOfVoter[key.voter], key)
+	}
+	for _, voter := range voters {
+		voterHex := hex.EncodeToString([]byte(voter))
+		var changed []bucketKey
+		for _, key := range bucketsOfVoter[voter] {
+			if !sameStake(fromBuckets[key], toBuckets[key]) {
+				changed = append(changed, key)
+			}
+		}
+		fromVoter := sumStakes(fromBuckets, bucketsOfVoter[voter])
+		toVoter := sumStakes(toBuckets, bucketsOfVoter[voter])
+		// a voter is written if any of its buckets changed, even if its totals are the same
+		if err := writeDiff(writer, delegate, rowVoter, voterHex, "", fromVoter, toVoter, len(changed) != 0); err != nil {
+			return err
+		}
+		for _, key := range changed {
+			if err := writeDiff(
+				writer,
+				delegate,
+				rowBucket,
+				voterHex,
+				key.startTime,
+				fromBuckets[key],
+				toBuckets[key],
+				false,
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// delegateStakes returns the tokens and votes of delegates, keyed by name
+func delegateStakes(result *types.ElectionResult) map[string]*VoteStake {
+	stakes := make(map[string]*VoteStake)
+	for _, delegate := range result.Delegates() {
+		tokens := big.NewInt(0)
+		for _, vote := range result.VotesByDelegate(delegate.Name()) {
+			tokens.Add(tokens, vote.Amount())
+		}
+		stakes[string(delegate.Name())] = &VoteStake{Tokens: tokens, Votes: delegate.Score()}
+	}
+	return stakes
+}
+
+// bucketStakes sums the tokens and votes of each bucket, and returns them along with the bucket keys in the order
+// of votes. Buckets of a voter starting at the same time are summed as one.
+func bucketStakes(votes []*types.Vote) (map[bucketKey]*VoteStake, []bucketKey) {
+	stakes := make(map[bucketKey]*VoteStake)
+	var keys []bucketKey
+	for _, vote := range votes {
+		key := bucketKey{
+			voter:     string(vote.Voter()),
+			startTime: vote.StartTime().UTC().Format(time.RFC3339),
+		}
+		stake, ok := stakes[key]
+		if !ok {
+			stake = &VoteStake{Tokens: big.NewInt(0), Votes: big.NewInt(0)}
+			stakes[key] = stake
+			keys = append(keys, key)
+		}
+		stake.Tokens.Add(stake.Tokens, vote.Amount())
+		stake.Votes.Add(stake.Votes, vote.WeightedAmount())
+	}
+	return stakes, keys
+}
+
+// sumStakes returns the total of the stakes of keys, or nil if none of them has stake
+func sumStakes(stakes map[bucketKey]*VoteStake, keys []bucketKey) *VoteStake {
+	var sum *VoteStake
+	for _, key := range keys {
+		stake, ok := stakes[key]
+		if !ok {
+			continue
+		}
+		if sum == nil {
+			sum = &VoteStake{Tokens: big.NewInt(0), Votes: big.NewInt(0)}
+		}
+		sum.Tokens.Add(sum.Tokens, stake.Tokens)
+		sum.Votes.Add(sum.Votes, stake.Votes)
+	}
+	return sum
+}
+
+// sameStake returns true if both stakes exist with the same tokens and votes
+func sameStake(from *VoteStake, to *VoteStake) bool {
+	return from != nil && to != nil && from.Tokens.Cmp(to.Tokens) == 0 && from.Votes.Cmp(to.Votes) == 0
+}
+
+// writeDiff writes the change between two stakes. Unchanged stakes are skipped unless keepUnchanged is set, e.g.,
+// for the totals of delegates.
+func writeDiff(
+	writer *tableWriter,
+	delegate string,
+	rowType string,
+	voter string,
+	startTime string,
+	from *VoteStake,
+	to *VoteStake,
+	keepUnchanged bool,
+) error {
+	zero := &VoteStake{Tokens: big.NewInt(0), Votes: big.NewInt(0)}
+	var change string
+	switch {
+	case from == nil:
+		change = changeJoined
+		from = zero
+	case to == nil:
+		change = changeLeft
+		to = zero
+	case sameStake(from, to):
+		if !keepUnchanged {
+			return nil
+		}
+		change = changeUnchanged
+	case from.Tokens.Cmp(to.Tokens) == 0 && from.Votes.Cmp(to.Votes) > 0:
+		change = changeDecayed
+	default:
+		change = changeChanged
+	}
+	if err := writer.Write(
+		delegate,
+		rowType,
+		voter,
+		startTime,
+		change,
+		from.Tokens,
+		to.Tokens,
+		from.Votes,
+		to.Votes,
+		new(big.Int).Sub(to.Votes, from.Votes),
+	); err != nil {
+		return errors.Wrap(err, "error writing diff")
+	}
+	return nil
+}
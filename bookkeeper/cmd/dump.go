@@ -0,0 +1,203 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	epoch        uint64
+	height       uint64
+	outputFormat string
+	columns      []string
+	delegates    []string
+	voters       []string
+	minAmount    string
+)
+
+// voteColumns are the columns of vote dump
+var voteColumns = []string{
+	"voter",
+	"startTime",
+	"duration",
+	"decay",
+	"tokens",
+	"votes",
+	"votee",
+	"voterIoAddr",
+}
+
+// DumpCmd dumps the votes of all delegates at a gravity chain height
+var DumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Dump votes of all delegates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		filter, err := newVoteFilter(delegates, voters, minAmount)
+		if err != nil {
+			return err
+		}
+		writer, err := newTableWriter(os.Stdout, outputFormat, voteColumns, columns)
+		if err != nil {
+			return err
+		}
+		result, err := fetchResult(configPath, endpoint, epoch, height)
+		if err != nil {
+			return err
+		}
+		return dumpVotes(result, filter, writer)
+	},
+}
+
+// voteFilter selects votes by delegate, voter and amount
+type voteFilter struct {
+	delegates [][]byte
+	voters    [][]byte
+	minAmount *big.Int
+}
+
+func init() {
+	addConfigFlag(DumpCmd.PersistentFlags())
+	addEndpointFlags(DumpCmd.PersistentFlags())
+	addSnapshotFlags(DumpCmd.PersistentFlags())
+	DumpCmd.PersistentFlags().Uint64Var(&epoch, "epoch", 0, "iotex epoch")
+	DumpCmd.PersistentFlags().Uint64Var(&height, "height", 0, "ethereum height")
+	DumpCmd.PersistentFlags().StringVarP(&outputFormat, "format", "f", formatCSV, "output format, csv|json|ndjson")
+	DumpCmd.PersistentFlags().StringSliceVar(&columns, "columns", nil, "comma separated columns to output, all if not specified")
+	DumpCmd.Flags().StringSliceVar(&delegates, "delegate", nil, "only dump votes of the delegates")
+	DumpCmd.Flags().StringSliceVar(&voters, "voter", nil, "only dump votes of the io or 0x voter addresses")
+	DumpCmd.Flags().StringVar(&minAmount, "min-amount", "0", "only dump votes of at least the amount of tokens in IOTX")
+}
+
+func newVoteFilter(delegates []string, voters []string, minAmount string) (*voteFilter, error) {
+	filter := &voteFilter{}
+	for _, delegate := range delegates {
+		name, err := decodeDelegateName(delegate)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse delegate name %s", delegate)
+		}
+		filter.delegates = append(filter.delegates, name)
+	}
+	for _, voter := range voters {
+		addr, err := util.ParseAddress(voter)
+		if err != nil {
+			return nil, err
+		}
+		filter.voters = append(filter.voters, addr.Bytes())
+	}
+	amount, _, err := util.ParseAmount(minAmount, util.IOTXDecimals, util.RoundUp)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid min amount")
+	}
+	filter.minAmount = amount
+	return filter, nil
+}
+
+// matchDelegate returns true if the delegate is selected
+func (f *voteFilter) matchDelegate(name []byte) bool {
+	return len(f.delegates) == 0 || containsBytes(f.delegates, name)
+}
+
+// matchVote returns true if the vote is selected
+func (f *voteFilter) matchVote(vote *types.Vote) bool {
+	if len(f.voters) != 0 && !containsBytes(f.voters, vote.Voter()) {
+		return false
+	}
+	return vote.Amount().Cmp(f.minAmount) >= 0
+}
+
+func containsBytes(list [][]byte, target []byte) bool {
+	for _, b := range list {
+		if bytes.Equal(b, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchResult fetches the election result at the gravity chain height, or at the start height of the epoch
+// if epoch is specified
+func fetchResult(configPath string, endpoint string, epoch uint64, height uint64) (*types.ElectionResult, error) {
+	committee, err := newCommittee(configPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create committee")
+	}
+	if height, err = resolveHeight(endpoint, epoch, height); err != nil {
+		return nil, err
+	}
+	result, err := committee.FetchResultByHeight(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch result at height %d", height)
+	}
+	return result, nil
+}
+
+// resolveHeight returns the gravity chain start height of the epoch if epoch is specified, or height otherwise
+func resolveHeight(endpoint string, epoch uint64, height uint64) (uint64, error) {
+	if epoch == 0 {
+		return height, nil
+	}
+	height, err := gravityChainHeight(endpoint, epoch)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get gravity chain height for epoch %d", epoch)
+	}
+	zap.L().Warn(
+		"Use gravity chain start height read from iotex chain",
+		zap.Uint64("height", height),
+		zap.Uint64("epoch", epoch),
+	)
+	return height, nil
+}
+
+func dumpVotes(result *types.ElectionResult, filter *voteFilter, writer *tableWriter) error {
+	for _, delegate := range result.Delegates() {
+		if !filter.matchDelegate(delegate.Name()) {
+			continue
+		}
+		for _, vote := range result.VotesByDelegate(delegate.Name()) {
+			if !filter.matchVote(vote) {
+				continue
+			}
+			ioAddr, err := address.FromBytes(vote.Voter())
+			if err != nil {
+				return err
+			}
+			if err := writer.Write(
+				hex.EncodeToString(vote.Voter()),
+				vote.StartTime().UTC().Format(time.RFC3339),
+				int64(vote.Duration().Seconds()),
+				vote.Decay(),
+				vote.Amount(),
+				vote.WeightedAmount(),
+				delegateNameString(vote.Candidate()),
+				ioAddr.String(),
+			); err != nil {
+				return errors.Wrap(err, "error writing vote")
+			}
+		}
+	}
+	return writer.Close()
+}
+
+// delegateNameString returns the readable name of delegate without zero padding
+func delegateNameString(name []byte) string {
+	return strings.Trim(string(name), "\x00")
+}
@@ -0,0 +1,437 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/iotexproject/iotex-tools/util"
+)
+
+func fetchFixture(t *testing.T, height uint64) *types.ElectionResult {
+	t.Helper()
+	result, err := newFakeCommittee(t).FetchResultByHeight(height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// writeRows writes with a json table writer of the columns, and returns the rows written
+func writeRows(t *testing.T, columns []string, write func(*tableWriter) error) []map[string]interface{} {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := newTableWriter(&buf, formatJSON, columns, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := write(writer); err != nil {
+		t.Fatal(err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("invalid json %s: %v", buf.String(), err)
+	}
+	return rows
+}
+
+func TestDumpVotes(t *testing.T) {
+	result := fetchFixture(t, 100)
+	for _, test := range []struct {
+		delegates []string
+		voters    []string
+		minAmount string
+		expected  []string
+	}{
+		{nil, nil, "0", []string{voterA, voterB, voterB, voterC}},
+		{[]string{"robotbp"}, nil, "0", []string{voterB, voterC}},
+		{nil, []string{"0x" + voterB}, "0", []string{voterB, voterB}},
+		{nil, nil, "500", []string{voterA, voterB, voterB}},
+		{[]string{"iotexlab"}, []string{"0x" + voterC}, "0", nil},
+	} {
+		filter, err := newVoteFilter(test.delegates, test.voters, test.minAmount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := writeRows(t, voteColumns, func(w *tableWriter) error {
+			return dumpVotes(result, filter, w)
+		})
+		var voters []string
+		for _, row := range rows {
+			voters = append(voters, row["voter"].(string))
+		}
+		if strings.Join(voters, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%v %v %s: expecting voters %v, but got %v", test.delegates, test.voters, test.minAmount, test.expected, voters)
+		}
+	}
+}
+
+func TestDumpVotesColumns(t *testing.T) {
+	filter, err := newVoteFilter([]string{"robotbp"}, []string{"0x" + voterC}, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := writeRows(t, voteColumns, func(w *tableWriter) error {
+		return dumpVotes(fetchFixture(t, 100), filter, w)
+	})
+	if len(rows) != 1 {
+		t.Fatalf("expecting 1 vote, but got %d", len(rows))
+	}
+	row := rows[0]
+	if row["startTime"] != "2019-04-25T00:00:00Z" {
+		t.Errorf("expecting start time in RFC3339, but got %v", row["startTime"])
+	}
+	if row["duration"] != float64(240*3600) {
+		t.Errorf("expecting duration in seconds, but got %v", row["duration"])
+	}
+	if row["decay"] != true || row["votee"] != "robotbp" || row["tokens"] != iotx(100).String() {
+		t.Errorf("unexpected vote %v", row)
+	}
+}
+
+func TestNewVoteFilterErrors(t *testing.T) {
+	if _, err := newVoteFilter(nil, []string{"0x1234"}, "0"); err == nil {
+		t.Error("expecting error of invalid voter")
+	}
+	if _, err := newVoteFilter(nil, nil, "ten"); err == nil {
+		t.Error("expecting error of invalid min amount")
+	}
+}
+
+func TestSummarizeDelegates(t *testing.T) {
+	summaries := summarizeDelegates(fetchFixture(t, 100))
+	if len(summaries) != 2 {
+		t.Fatalf("expecting 2 delegates, but got %d", len(summaries))
+	}
+	lab, robot := summaries[0], summaries[1]
+	if lab.Rank != 1 || lab.Name != "iotexlab" || robot.Rank != 2 || robot.Name != "robotbp" {
+		t.Errorf("unexpected ranks %d %s and %d %s", lab.Rank, lab.Name, robot.Rank, robot.Name)
+	}
+	if lab.Votes.Cmp(iotx(3000)) != 0 || lab.Tokens.Cmp(iotx(3000)) != 0 || lab.Voters != 2 {
+		t.Errorf("unexpected summary of iotexlab %+v", lab)
+	}
+	if lab.OperatorAddress != "io1operatoriotexlab" || lab.RewardAddress != "io1rewardiotexlab" {
+		t.Errorf("unexpected addresses of iotexlab %s and %s", lab.OperatorAddress, lab.RewardAddress)
+	}
+	if robot.Tokens.Cmp(iotx(600)) != 0 || robot.Votes.Cmp(iotx(600)) <= 0 || robot.Voters != 2 {
+		t.Errorf("unexpected summary of robotbp %+v", robot)
+	}
+	share, ok := new(big.Rat).SetString(lab.Share)
+	if !ok || share.Cmp(big.NewRat(80, 1)) <= 0 || share.Cmp(big.NewRat(84, 1)) >= 0 {
+		t.Errorf("expecting share of iotexlab around 83%%, but got %s", lab.Share)
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	filter, err := newVoteFilter(nil, nil, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := writeRows(t, diffColumns, func(w *tableWriter) error {
+		return diffResults(fetchFixture(t, 100), fetchFixture(t, 200), filter, w)
+	})
+	// unchanged voters, e.g., B, are skipped
+	expected := []string{
+		"iotexlab:delegate::" + changeChanged,
+		"iotexlab:voter:" + voterA + ":" + changeLeft,
+		"iotexlab:bucket:" + voterA + ":" + changeLeft,
+		"iotexlab:voter:" + voterD + ":" + changeJoined,
+		"iotexlab:bucket:" + voterD + ":" + changeJoined,
+		"robotbp:delegate::" + changeDecayed,
+		"robotbp:voter:" + voterC + ":" + changeDecayed,
+		"robotbp:bucket:" + voterC + ":" + changeDecayed,
+	}
+	if changes := diffChanges(rows); strings.Join(changes, ",") != strings.Join(expected, ",") {
+		t.Errorf("expecting changes %v, but got %v", expected, changes)
+	}
+	if rows[0]["deltaVotes"] != new(big.Int).Neg(iotx(700)).String() {
+		t.Errorf("expecting iotexlab to lose 700 IOTX of votes, but got %v", rows[0]["deltaVotes"])
+	}
+	if rows[2]["startTime"] != "2019-04-01T00:00:00Z" || rows[0]["startTime"] != "" || rows[1]["startTime"] != "" {
+		t.Errorf("expecting start time of bucket only, but got %v", rows[:3])
+	}
+}
+
+func TestDiffResultsByBucket(t *testing.T) {
+	filter, err := newVoteFilter(nil, nil, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := writeRows(t, diffColumns, func(w *tableWriter) error {
+		return diffResults(fetchFixture(t, 100), fetchFixture(t, 400), filter, w)
+	})
+	// the restaked bucket of B is a different bucket of the same tokens and votes, so B is unchanged in total
+	expected := []string{
+		"iotexlab:delegate::" + changeUnchanged,
+		"robotbp:delegate::" + changeChanged,
+		"robotbp:voter:" + voterB + ":" + changeUnchanged,
+		"robotbp:bucket:" + voterB + ":" + changeLeft,
+		"robotbp:bucket:" + voterB + ":" + changeJoined,
+		"robotbp:voter:" + voterC + ":" + changeLeft,
+		"robotbp:bucket:" + voterC + ":" + changeLeft,
+	}
+	if changes := diffChanges(rows); strings.Join(changes, ",") != strings.Join(expected, ",") {
+		t.Errorf("expecting changes %v, but got %v", expected, changes)
+	}
+	if rows[4]["startTime"] != "2019-05-02T00:00:00Z" || rows[4]["toTokens"] != iotx(500).String() {
+		t.Errorf("unexpected restaked bucket %v", rows[4])
+	}
+}
+
+func TestDiffVoters(t *testing.T) {
+	startTime := time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)
+	vote := func(voter string, start time.Time, amount int64) *types.Vote {
+		raw, err := hex.DecodeString(voter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := types.NewVote(start, 0, iotx(amount), iotx(amount), raw, delegateName(t, "iotexlab"), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	from := []*types.Vote{
+		vote(voterA, startTime, 100),
+		vote(voterA, startTime.Add(time.Hour), 50),
+		vote(voterB, startTime, 10),
+	}
+	// A adds tokens to its first bucket, while B is unchanged
+	to := []*types.Vote{
+		vote(voterB, startTime, 10),
+		vote(voterA, startTime, 150),
+		vote(voterA, startTime.Add(time.Hour), 50),
+	}
+	rows := writeRows(t, diffColumns, func(w *tableWriter) error {
+		if err := diffVoters(w, "iotexlab", from, to); err != nil {
+			return err
+		}
+		return w.Close()
+	})
+	expected := []string{
+		"iotexlab:voter:" + voterA + ":" + changeChanged,
+		"iotexlab:bucket:" + voterA + ":" + changeChanged,
+	}
+	if changes := diffChanges(rows); strings.Join(changes, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expecting changes %v, but got %v", expected, changes)
+	}
+	// the voter sums all its buckets
+	if rows[0]["fromTokens"] != iotx(150).String() || rows[0]["toTokens"] != iotx(200).String() {
+		t.Errorf("expecting voter tokens from %d to %d, but got %v", iotx(150), iotx(200), rows[0])
+	}
+	if rows[1]["deltaVotes"] != iotx(50).String() || rows[1]["startTime"] != "2019-04-01T00:00:00Z" {
+		t.Errorf("expecting bucket of 50 IOTX more votes, but got %v", rows[1])
+	}
+}
+
+// diffChanges returns the delegate, type, voter and change of diff rows
+func diffChanges(rows []map[string]interface{}) []string {
+	var changes []string
+	for _, row := range rows {
+		changes = append(
+			changes,
+			row["delegate"].(string)+":"+row["type"].(string)+":"+row["voter"].(string)+":"+row["change"].(string),
+		)
+	}
+	return changes
+}
+
+func TestDiffResultsOfDelegate(t *testing.T) {
+	filter, err := newVoteFilter([]string{"robotbp"}, nil, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := writeRows(t, diffColumns, func(w *tableWriter) error {
+		return diffResults(fetchFixture(t, 100), fetchFixture(t, 100), filter, w)
+	})
+	if len(rows) != 1 || rows[0]["delegate"] != "robotbp" || rows[0]["change"] != changeUnchanged {
+		t.Errorf("expecting robotbp unchanged only, but got %v", rows)
+	}
+}
+
+func TestVoterPortfolio(t *testing.T) {
+	voter, err := util.ParseAddress("0x" + voterB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := writeRows(t, voterColumns, func(w *tableWriter) error {
+		return voterPortfolio(fetchFixture(t, 100), voter.Bytes(), w)
+	})
+	var got []string
+	for _, row := range rows {
+		got = append(got, row["delegate"].(string)+":"+row["type"].(string)+":"+row["tokens"].(string))
+	}
+	expected := []string{
+		"iotexlab:bucket:" + iotx(2000).String(),
+		"iotexlab:total:" + iotx(2000).String(),
+		"robotbp:bucket:" + iotx(500).String(),
+		"robotbp:total:" + iotx(500).String(),
+		":total:" + iotx(2500).String(),
+	}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("expecting %v, but got %v", expected, got)
+	}
+	if rows[0]["expiry"] != "" {
+		t.Errorf("expecting no expiry of non-decaying bucket, but got %v", rows[0]["expiry"])
+	}
+}
+
+func TestVerifyWeights(t *testing.T) {
+	rows := writeRows(t, weightColumns, func(w *tableWriter) error {
+		return verifyWeights(fetchFixture(t, 100), big.NewInt(0), w)
+	})
+	if len(rows) != 0 {
+		t.Errorf("expecting no mismatch, but got %v", rows)
+	}
+	var buf bytes.Buffer
+	writer, err := newTableWriter(&buf, formatCSV, weightColumns, []string{"voter", "delta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyWeights(fetchFixture(t, 300), big.NewInt(0), writer); err == nil {
+		t.Error("expecting error of mismatched weight")
+	}
+	if buf.String() != "voter,delta\n"+voterA+",-1\n" {
+		t.Errorf("unexpected mismatches %q", buf.String())
+	}
+	writer, err = newTableWriter(&bytes.Buffer{}, formatCSV, weightColumns, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyWeights(fetchFixture(t, 300), big.NewInt(1), writer); err != nil {
+		t.Errorf("expecting mismatch within tolerance to pass, but got %v", err)
+	}
+}
+
+func TestForecast(t *testing.T) {
+	result := fetchFixture(t, 100)
+	rows := writeRows(t, forecastColumns, func(w *tableWriter) error {
+		return forecast(result, delegateName(t, "robotbp"), 7, 3, w)
+	})
+	var got []string
+	for _, row := range rows {
+		got = append(got, row["date"].(string)+":"+row["type"].(string))
+	}
+	expected := []string{
+		"2019-05-04T00:00:00Z:total",
+		"2019-05-05T00:00:00Z:expiry",
+		"2019-05-07T00:00:00Z:total",
+		"2019-05-08T00:00:00Z:total",
+	}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expecting %v, but got %v", expected, got)
+	}
+	// the decaying bucket of voter C is left with its tokens after expiry
+	if rows[1]["voter"] != voterC || rows[1]["projected"] != iotx(100).String() {
+		t.Errorf("unexpected expiry %v", rows[1])
+	}
+	if rows[3]["projected"] != iotx(600).String() {
+		t.Errorf("expecting projected votes of robotbp to be its tokens, but got %v", rows[3]["projected"])
+	}
+	if err := forecast(result, delegateName(t, "unknown"), 7, 1, rowsWriter(t)); err == nil {
+		t.Error("expecting error of unknown delegate")
+	}
+}
+
+func rowsWriter(t *testing.T) *tableWriter {
+	t.Helper()
+	writer, err := newTableWriter(&bytes.Buffer{}, formatJSON, forecastColumns, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writer
+}
+
+func TestFetchResultFromSnapshots(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshots")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	committee := newFakeCommittee(t)
+	archive, err := util.NewSnapshotCommittee(dir, committee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := archive.FetchResultByHeight(100); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, util.SnapshotFileName(100))); err != nil {
+		t.Fatalf("expecting snapshot of height 100 to be archived: %v", err)
+	}
+
+	defer func(dir string, off bool) {
+		snapshotDir, offline = dir, off
+	}(snapshotDir, offline)
+	snapshotDir, offline = dir, true
+	result, err := fetchResult("", "", 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if committee.Fetches(100) != 1 {
+		t.Errorf("expecting 1 fetch from gravity chain, but got %d", committee.Fetches(100))
+	}
+	if !result.MintTime().Equal(time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected mint time %s", result.MintTime())
+	}
+	summaries := summarizeDelegates(result)
+	if len(summaries) != 2 || summaries[0].Votes.Cmp(iotx(3000)) != 0 {
+		t.Errorf("unexpected result replayed from snapshot %+v", summaries)
+	}
+	if _, err := fetchResult("", "", 0, 200); err == nil {
+		t.Error("expecting error of missing snapshot offline")
+	}
+	// a snapshot of another height under the name of height 200 is rejected
+	if err := util.WriteSnapshot(filepath.Join(dir, util.SnapshotFileName(200)), 100, result); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fetchResult("", "", 0, 200); err == nil {
+		t.Error("expecting error of snapshot of another height")
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("expecting only 2 snapshots in %s, but got %d files", dir, len(files))
+	}
+}
+
+func TestVoteSeriesOffline(t *testing.T) {
+	server, addr, stop := startFakeChain(t)
+	defer stop()
+	defer func(dir string, off bool) {
+		snapshotDir, offline = dir, off
+	}(snapshotDir, offline)
+	snapshotDir, offline = os.TempDir(), true
+	writer, err := newTableWriter(ioutil.Discard, formatJSON, seriesColumns, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := newVoteFilter(nil, nil, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = voteSeries("", addr, 1, 2, 1, filter, writer)
+	if err == nil || !strings.Contains(err.Error(), "--offline") {
+		t.Errorf("expecting error of --offline, but got %v", err)
+	}
+	// the error is returned before any access to the iotex endpoint
+	if server.Calls("GetEpochMeta") != 0 {
+		t.Errorf("expecting no call of GetEpochMeta, but got %d", server.Calls("GetEpochMeta"))
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"crypto/tls"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// defaultEndpoint is the default iotex endpoint
+const defaultEndpoint = "api.iotex.one:443"
+
+var insecure bool
+
+// addEndpointFlags adds the flags of iotex endpoint
+func addEndpointFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&endpoint, "endpoint", "e", defaultEndpoint, "iotex endpoint")
+	flags.BoolVar(&insecure, "insecure", false, "connect to iotex endpoint without TLS")
+	addNetworkFlags(flags)
+}
+
+// dial connects to iotex endpoint, with TLS unless insecure is set
+func dial(endpoint string) (*grpc.ClientConn, error) {
+	if insecure {
+		return grpc.Dial(endpoint, grpc.WithInsecure())
+	}
+	return grpc.Dial(endpoint, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+}
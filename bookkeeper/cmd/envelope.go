@@ -0,0 +1,232 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/protogen/iotextypes"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+)
+
+// actionVersion is the version of action core
+const actionVersion = 1
+
+// Envelope is an unsigned execution of multisend contract, which is built on a networked host and signed offline.
+// It is either a payout or an admin call of the contract.
+type Envelope struct {
+	Contract string `json:"contract"`
+	// Amount is the value sent to the contract, i.e., the total amount of a sendCoin payout plus tips, or only tips
+	// for a sendToken payout, whose total is in the token
+	Amount   string `json:"amount"`
+	Total    string `json:"total"`
+	Tips     string `json:"tips"`
+	Nonce    uint64 `json:"nonce"`
+	GasLimit uint64 `json:"gasLimit"`
+	GasPrice string `json:"gasPrice"`
+	Data     string `json:"data"`
+}
+
+// SignedAction is an envelope signed by the payout key
+type SignedAction struct {
+	Envelope Envelope `json:"envelope"`
+	Sender   string   `json:"sender"`
+	Hash     string   `json:"hash"`
+	// Action is the hex encoded action in protobuf
+	Action string `json:"action"`
+}
+
+// Core validates the envelope and returns the action core to sign
+func (e *Envelope) Core() (*iotextypes.ActionCore, error) {
+	if _, err := address.FromString(e.Contract); err != nil {
+		return nil, errors.Wrapf(err, "invalid contract address %s", e.Contract)
+	}
+	amount, err := parseRau("amount", e.Amount)
+	if err != nil {
+		return nil, err
+	}
+	total, err := parseRau("total", e.Total)
+	if err != nil {
+		return nil, err
+	}
+	tips, err := parseRau("tips", e.Tips)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseRau("gas price", e.GasPrice); err != nil {
+		return nil, err
+	}
+	if e.Nonce == 0 {
+		return nil, errors.New("nonce is not specified")
+	}
+	if e.GasLimit == 0 {
+		return nil, errors.New("gas limit is not specified")
+	}
+	data, err := hex.DecodeString(e.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode data")
+	}
+	value, err := checkEnvelopeData(data, total)
+	if err != nil {
+		return nil, err
+	}
+	if new(big.Int).Add(value, tips).Cmp(amount) != 0 {
+		return nil, errors.Errorf("amount %s does not equal to %d sent by the call plus tips %s", e.Amount, value, e.Tips)
+	}
+	return &iotextypes.ActionCore{
+		Version:  actionVersion,
+		Nonce:    e.Nonce,
+		GasLimit: e.GasLimit,
+		GasPrice: e.GasPrice,
+		Action: &iotextypes.ActionCore_Execution{
+			Execution: &iotextypes.Execution{
+				Amount:   e.Amount,
+				Contract: e.Contract,
+				Data:     data,
+			},
+		},
+	}, nil
+}
+
+// Verify checks that the signed action is the envelope signed by the sender, and returns the action
+func (s *SignedAction) Verify() (*iotextypes.Action, error) {
+	core, err := s.Envelope.Core()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid envelope")
+	}
+	actBytes, err := hex.DecodeString(s.Action)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode action")
+	}
+	var act iotextypes.Action
+	if err := proto.Unmarshal(actBytes, &act); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal action")
+	}
+	expected, err := proto.Marshal(core)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := proto.Marshal(act.Core)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(expected, signed) {
+		return nil, errors.New("signed action does not match the envelope")
+	}
+	coreHash := blake2b.Sum256(signed)
+	if len(act.Signature) != 65 || !crypto.VerifySignature(act.SenderPubKey, coreHash[:], act.Signature[:64]) {
+		return nil, errors.New("invalid signature")
+	}
+	pubKey, err := crypto.UnmarshalPubkey(act.SenderPubKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid sender public key")
+	}
+	sender, err := address.FromBytes(crypto.PubkeyToAddress(*pubKey).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if sender.String() != s.Sender {
+		return nil, errors.Errorf("action is signed by %s rather than %s", sender.String(), s.Sender)
+	}
+	actHash := blake2b.Sum256(actBytes)
+	if hex.EncodeToString(actHash[:]) != s.Hash {
+		return nil, errors.Errorf("action hash %x does not match %s", actHash, s.Hash)
+	}
+	return &act, nil
+}
+
+// checkEnvelopeData checks that data calls a multisend method, and that a payout pays the total amount while
+// any other method pays nothing. It returns the value in Rau the call sends besides tips, which is the total of
+// sendCoin, and zero otherwise.
+func checkEnvelopeData(data []byte, total *big.Int) (*big.Int, error) {
+	if len(data) < 4 {
+		return nil, errors.Errorf("data %x is too short", data)
+	}
+	multisendABI, err := abi.JSON(strings.NewReader(MultisendABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid multisend abi")
+	}
+	method, err := multisendABI.MethodById(data[:4])
+	if err != nil {
+		return nil, errors.Wrapf(err, "unknown method %x", data[:4])
+	}
+	switch method.Name {
+	case sendCoin, sendToken:
+		payout, err := decodeBytecode(data)
+		if err != nil {
+			return nil, err
+		}
+		if payout.Total().Cmp(total) != 0 {
+			return nil, errors.Errorf("total %d does not match the payout total %d in data", total, payout.Total())
+		}
+		if method.Name == sendToken {
+			return big.NewInt(0), nil
+		}
+		return total, nil
+	default:
+		if _, err := method.Inputs.UnpackValues(data[4:]); err != nil {
+			return nil, errors.Wrapf(err, "failed to unpack arguments of %s", method.Name)
+		}
+		if total.Sign() != 0 {
+			return nil, errors.Errorf("method %s should not send any amount, but total is %d", method.Name, total)
+		}
+		return big.NewInt(0), nil
+	}
+}
+
+// signCore signs the action core with the private key
+func signCore(core *iotextypes.ActionCore, key *ecdsa.PrivateKey) (*iotextypes.Action, error) {
+	coreBytes, err := proto.Marshal(core)
+	if err != nil {
+		return nil, err
+	}
+	coreHash := blake2b.Sum256(coreBytes)
+	signature, err := crypto.Sign(coreHash[:], key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign action")
+	}
+	return &iotextypes.Action{
+		Core:         core,
+		SenderPubKey: crypto.FromECDSAPub(&key.PublicKey),
+		Signature:    signature,
+	}, nil
+}
+
+func parseRau(name string, value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, errors.Errorf("invalid %s %s", name, value)
+	}
+	return amount, nil
+}
+
+func readJSON(filename string, v interface{}) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal(data, v), "failed to parse %s", filename)
+}
+
+func writeJSON(filename string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
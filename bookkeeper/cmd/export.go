@@ -9,12 +9,12 @@ package cmd
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/csv"
 	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -24,13 +24,10 @@ import (
 	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-core/action/protocol/rewarding/rewardingpb"
 	"github.com/iotexproject/iotex-core/protogen/iotexapi"
-	"github.com/iotexproject/iotex-election/committee"
 	"github.com/iotexproject/iotex-tools/util"
 	"github.com/logrusorgru/aurora"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 var (
@@ -48,6 +45,8 @@ var (
 type Bucket struct {
 	owner  string
 	amount *big.Int
+	// tokens is the raw tokens of the bucket, while amount is its weighted votes
+	tokens *big.Int
 }
 
 // ExportCmd exports reward result into csv
@@ -57,26 +56,28 @@ var ExportCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
-		return export(configPath, args[0], start, to, endpoint, unit, percentage, withFoundationBonus, useIOAddr)
+		committee, err := newCommittee(configPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to create committee")
+		}
+		return export(committee, args[0], start, to, endpoint, unit, percentage, withFoundationBonus, useIOAddr, ".")
 	},
 }
 
 func init() {
-	ExportCmd.Flags().StringVar(&configPath, "config", "committee.yaml", "config file")
+	addConfigFlag(ExportCmd.Flags())
 	ExportCmd.Flags().Uint64Var(&start, "start", 0, "start epoch number")
 	ExportCmd.Flags().Uint64Var(&to, "to", 0, "to epoch number")
-	ExportCmd.Flags().StringVarP(&endpoint, "endpoint", "e", "api.iotex.one:443", "iotex endpoint")
+	addEndpointFlags(ExportCmd.Flags())
+	addSnapshotFlags(ExportCmd.Flags())
 	ExportCmd.Flags().UintVarP(&percentage, "percentage", "p", 100, "percentage")
 	ExportCmd.Flags().BoolVarP(&withFoundationBonus, "with-foundation-bonus", "w", false, "epoch bonus with foundation bonus")
 	ExportCmd.Flags().StringVarP(&unit, "unit", "u", "Rau", "unit of amount")
 	ExportCmd.Flags().BoolVarP(&useIOAddr, "in-io-address", "i", false, "output address in iotex format")
 }
 
-func export(configPath string, bp string, startEpoch uint64, toEpoch uint64, endpoint string, unit string, distPercentage uint, withFoundationBonus bool, useIOAddr bool) error {
-	committee, err := util.NewCommitteeWithConfigFile(configPath)
-	if err != nil {
-		return errors.Wrap(err, "failed to create committee %+v")
-	}
+// export writes the distribution of the rewards of bp from startEpoch to toEpoch into a csv file in outputDir
+func export(committee util.ResultFetcher, bp string, startEpoch uint64, toEpoch uint64, endpoint string, unit string, distPercentage uint, withFoundationBonus bool, useIOAddr bool, outputDir string) error {
 	if len(bp) == 0 {
 		return errors.New("bp name is invalid")
 	}
@@ -138,33 +139,40 @@ func export(configPath string, bp string, startEpoch uint64, toEpoch uint64, end
 		if reward.Sign() == 0 {
 			continue
 		}
-		reward = new(big.Int).Div(new(big.Int).Mul(reward, new(big.Int).SetUint64(uint64(distPercentage))), big.NewInt(100))
-		for _, bucket := range buckets {
-			if _, ok := distributions[bucket.owner]; !ok {
-				distributions[bucket.owner] = big.NewInt(0)
-			}
-			distributions[bucket.owner].Add(
-				distributions[bucket.owner],
-				new(big.Int).Div(new(big.Int).Mul(bucket.amount, reward), totalVotes),
-			)
-		}
+		distribute(distributions, buckets, reward, totalVotes, distPercentage)
 	}
 	fmt.Printf("The output amount unit is in %s.\n", unit)
 	filename := fmt.Sprintf("%s_epoch_%d_to_%d_in_%s.csv", delegateName, startEpoch, toEpoch, unit)
-	filename = strings.Replace(strings.Trim(filename, "\x00"), "\x00", "#", -1)
-	writeCSV(
+	filename = filepath.Join(outputDir, strings.Replace(strings.Trim(filename, "\x00"), "\x00", "#", -1))
+	if err := writeCSV(
 		filename,
 		useIOAddr,
 		distributions,
 		unit,
-	)
+	); err != nil {
+		return errors.Wrapf(err, "failed to write %s", filename)
+	}
 	fmt.Printf("csv format data has been written to %s\n", filename)
 	return nil
 }
 
+// distribute adds the percentage of reward to the owners of buckets, in proportion to the votes of buckets
+func distribute(distributions map[string]*big.Int, buckets []Bucket, reward *big.Int, totalVotes *big.Int, distPercentage uint) {
+	reward = new(big.Int).Div(new(big.Int).Mul(reward, new(big.Int).SetUint64(uint64(distPercentage))), big.NewInt(100))
+	for _, bucket := range buckets {
+		if _, ok := distributions[bucket.owner]; !ok {
+			distributions[bucket.owner] = big.NewInt(0)
+		}
+		distributions[bucket.owner].Add(
+			distributions[bucket.owner],
+			new(big.Int).Div(new(big.Int).Mul(bucket.amount, reward), totalVotes),
+		)
+	}
+}
+
 func getReward(endpoint string, epoch uint64, rewardAddress string, withFoundationBonus bool) (*big.Int, error) {
-	lastBlock := epoch * 24 * 15 // numDelegate: 24, subEpoch: 15
-	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	lastBlock := epoch * numDelegates * numSubEpochs
+	conn, err := dial(endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -239,7 +247,7 @@ func getReward(endpoint string, epoch uint64, rewardAddress string, withFoundati
 }
 
 func gravityChainHeight(endpoint string, epochNum uint64) (uint64, error) {
-	conn, err := grpc.Dial(endpoint, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	conn, err := dial(endpoint)
 	if err != nil {
 		return 0, err
 	}
@@ -256,7 +264,7 @@ func gravityChainHeight(endpoint string, epochNum uint64) (uint64, error) {
 func readEthereum(
 	height uint64,
 	delegateName []byte,
-	committee committee.Committee,
+	committee util.ResultFetcher,
 ) (rewardAddress string, totalVotes *big.Int, buckets []Bucket, err error) {
 	totalVotes = big.NewInt(0)
 	result, err := committee.FetchResultByHeight(height)
@@ -277,6 +285,7 @@ func readEthereum(
 		buckets = append(buckets, Bucket{
 			owner:  hex.EncodeToString(vote.Voter()),
 			amount: amount,
+			tokens: vote.Amount(),
 		})
 		totalVotes.Add(totalVotes, amount)
 	}
@@ -294,9 +303,6 @@ func decodeDelegateName(rawName string) ([]byte, error) {
 	return append(zeroBytes, []byte(rawName)...), nil
 }
 
-// OneIOTX is the amount of one IOTX in Rau
-var OneIOTX = new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
-
 func writeCSV(filename string, useIOAddr bool, distributions map[string]*big.Int, unit string) error {
 	file, err := os.Create(filename)
 	if err != nil {
@@ -307,20 +313,11 @@ func writeCSV(filename string, useIOAddr bool, distributions map[string]*big.Int
 	defer writer.Flush()
 	type Owner struct {
 		Addr   common.Address
-		Reward *big.Float
+		Reward *big.Int
 	}
 	var owners []Owner
 	for owner, reward := range distributions {
-		rewardInFloat := new(big.Float).SetInt(reward)
-		switch unit {
-		case "Rau":
-			// do nothing
-		case "IOTX":
-			rewardInFloat = new(big.Float).Quo(rewardInFloat, OneIOTX)
-		default:
-			return errors.Errorf("unit %s is not supported unit", unit)
-		}
-		owners = append(owners, Owner{common.HexToAddress(owner), rewardInFloat})
+		owners = append(owners, Owner{common.HexToAddress(owner), reward})
 	}
 	sort.Slice(owners, func(i, j int) bool {
 		return owners[i].Reward.Cmp(owners[j].Reward) >= 0
@@ -336,7 +333,16 @@ func writeCSV(filename string, useIOAddr bool, distributions map[string]*big.Int
 		} else {
 			addr = owner.Addr.String()
 		}
-		if err := writer.Write([]string{addr, owner.Reward.String()}); err != nil {
+		var reward string
+		switch unit {
+		case "Rau":
+			reward = owner.Reward.String()
+		case "IOTX":
+			reward = util.FormatIOTX(owner.Reward)
+		default:
+			return errors.Errorf("unit %s is not supported unit", unit)
+		}
+		if err := writer.Write([]string{addr, reward}); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,208 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/iotex-tools/test/fake"
+	"github.com/iotexproject/iotex-tools/util"
+)
+
+const (
+	// committeeFixture has the results at heights 100 and 200 of delegates iotexlab and robotbp, where the decaying
+	// bucket of C has a pinned weighted amount, the result at height 300 of iotexlab without reward address and with
+	// a wrong weighted amount, and the result at height 400 where B restakes to robotbp in a new bucket
+	committeeFixture = "testdata/committee.json"
+	voterA           = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	voterB           = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	voterC           = "cccccccccccccccccccccccccccccccccccccccc"
+	voterD           = "dddddddddddddddddddddddddddddddddddddddd"
+)
+
+func newFakeCommittee(t *testing.T) *fake.Committee {
+	t.Helper()
+	c, err := fake.NewCommitteeFromFile(committeeFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// iotx returns the amount of IOTX in Rau
+func iotx(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+}
+
+func delegateName(t *testing.T, name string) []byte {
+	t.Helper()
+	raw, err := decodeDelegateName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestDecodeDelegateName(t *testing.T) {
+	raw, err := decodeDelegateName("iotexlab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(raw) != 12 || string(raw[4:]) != "iotexlab" || raw[0] != 0 {
+		t.Errorf("unexpected raw name %x", raw)
+	}
+	hexRaw, err := decodeDelegateName("000000000000696f7465786c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(hexRaw[6:]) != "iotexl" {
+		t.Errorf("unexpected raw name %x of hex name", hexRaw)
+	}
+}
+
+func TestReadEthereum(t *testing.T) {
+	committee := newFakeCommittee(t)
+	rewardAddress, totalVotes, buckets, err := readEthereum(100, delegateName(t, "iotexlab"), committee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewardAddress != "io1rewardiotexlab" {
+		t.Errorf("expecting reward address io1rewardiotexlab, but got %s", rewardAddress)
+	}
+	if totalVotes.Cmp(iotx(3000)) != 0 {
+		t.Errorf("expecting total votes %d, but got %d", iotx(3000), totalVotes)
+	}
+	expected := map[string]*big.Int{voterA: iotx(1000), voterB: iotx(2000)}
+	if len(buckets) != len(expected) {
+		t.Fatalf("expecting %d buckets, but got %d", len(expected), len(buckets))
+	}
+	for _, bucket := range buckets {
+		if amount, ok := expected[bucket.owner]; !ok || amount.Cmp(bucket.amount) != 0 {
+			t.Errorf("unexpected bucket of %s with %d", bucket.owner, bucket.amount)
+		}
+	}
+}
+
+func TestReadEthereumWeightedVotes(t *testing.T) {
+	committee := newFakeCommittee(t)
+	// the bucket of voter C of 100 IOTX started at 2019-04-25 and decays in 10 days, whose weighted votes are pinned
+	// in fixture as 100 * (1 + log_1.2(remaining days) / 100) IOTX, computed in float64 as the gravity chain does
+	startTime := time.Date(2019, 4, 25, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		height   uint64
+		mintTime time.Time
+		weighted string
+	}{
+		// 4 days remaining
+		{100, time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), "107603568033847851737"},
+		// 3 days remaining
+		{200, time.Date(2019, 5, 2, 0, 0, 0, 0, time.UTC), "106025685102665478965"},
+	} {
+		expected, _ := new(big.Int).SetString(test.weighted, 10)
+		// the weighting formula reproduces the pinned weighted votes
+		if weighted := util.WeightedVotes(iotx(100), startTime, 240*time.Hour, true, test.mintTime); weighted.Cmp(expected) != 0 {
+			t.Errorf("expecting weighted votes %d of %s at %s, but got %d", expected, voterC, test.mintTime, weighted)
+		}
+		_, totalVotes, buckets, err := readEthereum(test.height, delegateName(t, "robotbp"), committee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var found bool
+		for _, bucket := range buckets {
+			if bucket.owner != voterC {
+				continue
+			}
+			found = true
+			if bucket.amount.Cmp(expected) != 0 || bucket.tokens.Cmp(iotx(100)) != 0 {
+				t.Errorf(
+					"expecting bucket of %s with %d votes and %d tokens at height %d, but got %d and %d",
+					voterC,
+					expected,
+					iotx(100),
+					test.height,
+					bucket.amount,
+					bucket.tokens,
+				)
+			}
+		}
+		if !found {
+			t.Errorf("expecting bucket of %s at height %d", voterC, test.height)
+		}
+		// B votes robotbp with 500 IOTX without lock
+		if total := new(big.Int).Add(iotx(500), expected); totalVotes.Cmp(total) != 0 {
+			t.Errorf("expecting total votes %d at height %d, but got %d", total, test.height, totalVotes)
+		}
+	}
+}
+
+func TestReadEthereumWithoutRewardAddress(t *testing.T) {
+	committee := newFakeCommittee(t)
+	for _, test := range []struct {
+		height   uint64
+		delegate string
+	}{
+		{100, "unknown"},
+		{300, "iotexlab"},
+	} {
+		rewardAddress, totalVotes, buckets, err := readEthereum(test.height, delegateName(t, test.delegate), committee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rewardAddress != "" || totalVotes.Sign() != 0 || len(buckets) != 0 {
+			t.Errorf(
+				"expecting nothing of %s at height %d, but got reward address %s, total votes %d and %d buckets",
+				test.delegate,
+				test.height,
+				rewardAddress,
+				totalVotes,
+				len(buckets),
+			)
+		}
+	}
+}
+
+func TestReadEthereumError(t *testing.T) {
+	committee := newFakeCommittee(t)
+	committee.SetError(100, errors.New("gravity chain is down"))
+	if _, _, _, err := readEthereum(100, delegateName(t, "iotexlab"), committee); err == nil {
+		t.Error("expecting error of fetch to be returned")
+	}
+	if _, _, _, err := readEthereum(150, delegateName(t, "iotexlab"), committee); err == nil {
+		t.Error("expecting error of height without result")
+	}
+}
+
+func TestDistribute(t *testing.T) {
+	committee := newFakeCommittee(t)
+	distributions := make(map[string]*big.Int)
+	for _, height := range []uint64{100, 200} {
+		_, totalVotes, buckets, err := readEthereum(height, delegateName(t, "iotexlab"), committee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 90% of 230 IOTX is distributed in each epoch
+		distribute(distributions, buckets, iotx(230), totalVotes, 90)
+	}
+	// A has 1/3 of votes at height 100 only, B has 2/3 at height 100 and 20/23 at height 200, and D has 3/23
+	// at height 200
+	expected := map[string]*big.Int{
+		voterA: iotx(69),
+		voterB: iotx(138 + 180),
+		voterD: iotx(27),
+	}
+	if len(distributions) != len(expected) {
+		t.Fatalf("expecting %d voters, but got %d", len(expected), len(distributions))
+	}
+	for voter, amount := range expected {
+		if distributions[voter] == nil || distributions[voter].Cmp(amount) != 0 {
+			t.Errorf("expecting %d to %s, but got %d", amount, voter, distributions[voter])
+		}
+	}
+}
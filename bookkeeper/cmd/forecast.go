@@ -0,0 +1,176 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// rowExpiry is the type of a row of bucket expiring
+	rowExpiry = "expiry"
+	// day is the duration of a day
+	day = 24 * time.Hour
+)
+
+var (
+	forecastDays uint
+	interval     uint
+)
+
+// forecastColumns are the columns of forecast. Voter is empty in the rows of totals.
+var forecastColumns = []string{
+	"date",
+	"type",
+	"voter",
+	"tokens",
+	"votes",
+	"projected",
+	"delta",
+}
+
+// ForecastCmd projects the votes of a delegate over the next days
+var ForecastCmd = &cobra.Command{
+	Use:   "forecast delegate",
+	Short: "Project the votes of a delegate and its expiring buckets over the next days",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if forecastDays == 0 || interval == 0 {
+			return errors.New("days and interval should be positive")
+		}
+		name, err := decodeDelegateName(args[0])
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse delegate name %s", args[0])
+		}
+		writer, err := newTableWriter(os.Stdout, outputFormat, forecastColumns, columns)
+		if err != nil {
+			return err
+		}
+		result, err := fetchResult(configPath, endpoint, epoch, height)
+		if err != nil {
+			return err
+		}
+		return forecast(result, name, forecastDays, interval, writer)
+	},
+}
+
+func init() {
+	DumpCmd.AddCommand(ForecastCmd)
+	ForecastCmd.Flags().UintVar(&forecastDays, "days", 30, "number of days to project")
+	ForecastCmd.Flags().UintVar(&interval, "interval", 1, "number of days between projected totals")
+}
+
+// forecast projects the votes of the delegate from the mint time of result. Each decaying bucket expiring within
+// the days is written on its expiry date, followed by the projected total of the delegate every interval days.
+// Non-decaying buckets keep their weights until decay is turned on, so they are projected as is.
+func forecast(result *types.ElectionResult, delegate []byte, days uint, interval uint, writer *tableWriter) error {
+	now := result.MintTime()
+	votes := result.VotesByDelegate(delegate)
+	if !containsDelegate(result, delegate) {
+		return errors.Errorf("delegate %s is not in the result", delegateNameString(delegate))
+	}
+	current := projectVotes(votes, now)
+	expiring := expiringVotes(votes, now, now.Add(time.Duration(days)*day))
+	var next int
+	for d := interval; ; d += interval {
+		if d > days {
+			d = days
+		}
+		date := now.Add(time.Duration(d) * day)
+		for ; next < len(expiring) && !expiry(expiring[next]).After(date); next++ {
+			vote := expiring[next]
+			at := expiry(vote)
+			before := util.WeightedVotes(vote.Amount(), vote.StartTime(), vote.Duration(), vote.Decay(), now)
+			after := util.WeightedVotes(vote.Amount(), vote.StartTime(), vote.Duration(), vote.Decay(), at)
+			if err := writer.Write(
+				at.UTC().Format(time.RFC3339),
+				rowExpiry,
+				hex.EncodeToString(vote.Voter()),
+				vote.Amount(),
+				before,
+				after,
+				new(big.Int).Sub(after, before),
+			); err != nil {
+				return errors.Wrap(err, "error writing expiry")
+			}
+		}
+		projected := projectVotes(votes, date)
+		if err := writer.Write(
+			date.UTC().Format(time.RFC3339),
+			rowTotal,
+			"",
+			sumTokens(votes),
+			current,
+			projected,
+			new(big.Int).Sub(projected, current),
+		); err != nil {
+			return errors.Wrap(err, "error writing total")
+		}
+		if d == days {
+			break
+		}
+	}
+	return writer.Close()
+}
+
+func containsDelegate(result *types.ElectionResult, name []byte) bool {
+	for _, delegate := range result.Delegates() {
+		if string(delegate.Name()) == string(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectVotes returns the total weighted votes of the buckets at the time
+func projectVotes(votes []*types.Vote, at time.Time) *big.Int {
+	total := big.NewInt(0)
+	for _, vote := range votes {
+		total.Add(total, util.WeightedVotes(vote.Amount(), vote.StartTime(), vote.Duration(), vote.Decay(), at))
+	}
+	return total
+}
+
+func sumTokens(votes []*types.Vote) *big.Int {
+	total := big.NewInt(0)
+	for _, vote := range votes {
+		total.Add(total, vote.Amount())
+	}
+	return total
+}
+
+// expiringVotes returns the decaying buckets expiring in (from, to], in the order of expiry
+func expiringVotes(votes []*types.Vote, from time.Time, to time.Time) []*types.Vote {
+	var expiring []*types.Vote
+	for _, vote := range votes {
+		if !vote.Decay() {
+			continue
+		}
+		if at := expiry(vote); at.After(from) && !at.After(to) {
+			expiring = append(expiring, vote)
+		}
+	}
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiry(expiring[i]).Before(expiry(expiring[j]))
+	})
+	return expiring
+}
+
+// expiry returns the end of the duration of a bucket
+func expiry(vote *types.Vote) time.Time {
+	return vote.StartTime().Add(vote.Duration())
+}
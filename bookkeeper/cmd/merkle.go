@@ -0,0 +1,182 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	merkleFile  string
+	claimIndex  uint64
+	claimOwner  string
+	claimAmount string
+	claimProof  string
+	proofFile   string
+)
+
+// MerkleDistribution is the merkle root of a claim-based payout and the proofs of all claims
+type MerkleDistribution struct {
+	Root   string            `json:"root"`
+	Total  string            `json:"total"`
+	Claims map[string]*Claim `json:"claims"`
+}
+
+// Claim is an entry of merkle distribution, keyed by the 0x address of recipient
+type Claim struct {
+	Index     uint64   `json:"index"`
+	IoAddress string   `json:"ioAddress"`
+	Amount    string   `json:"amount"`
+	Proof     []string `json:"proof"`
+}
+
+// VerifyProofCmd verifies a claim against a merkle root
+var VerifyProofCmd = &cobra.Command{
+	Use:   "verify-proof root",
+	Short: "Verify a claim entry against a merkle root",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return verifyProof(args[0])
+	},
+}
+
+func init() {
+	ConvertCmd.Flags().StringVar(&merkleFile, "merkle", "", "output file of merkle root and proofs for claim-based payout")
+	VerifyProofCmd.Flags().StringVar(&proofFile, "proof-file", "", "merkle distribution file to read the claim of address from")
+	VerifyProofCmd.Flags().StringVar(&claimOwner, "address", "", "io or 0x address of recipient")
+	VerifyProofCmd.Flags().Uint64Var(&claimIndex, "index", 0, "index of claim")
+	VerifyProofCmd.Flags().StringVar(&claimAmount, "amount", "", "amount of claim in Rau")
+	VerifyProofCmd.Flags().StringVar(&claimProof, "proof", "", "comma separated proof hashes")
+}
+
+// newMerkleDistribution builds a merkle tree over (index, address, amount) of the records, where index is the
+// position of record in the deduplicated records rather than the line in csv
+func newMerkleDistribution(records []*Record) (*MerkleDistribution, error) {
+	leaves := make([]common.Hash, len(records))
+	for i, record := range records {
+		leaves[i] = util.MerkleLeaf(uint64(i), record.Address, record.Amount)
+	}
+	tree, err := util.NewMerkleTree(leaves)
+	if err != nil {
+		return nil, err
+	}
+	_, _, total := splitRecords(records)
+	distribution := &MerkleDistribution{
+		Root:   tree.Root().Hex(),
+		Total:  total.String(),
+		Claims: make(map[string]*Claim),
+	}
+	for i, record := range records {
+		proof, err := tree.Proof(i)
+		if err != nil {
+			return nil, err
+		}
+		ioAddr, err := address.FromBytes(record.Address.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		claim := &Claim{
+			Index:     uint64(i),
+			IoAddress: ioAddr.String(),
+			Amount:    record.Amount.String(),
+		}
+		for _, hash := range proof {
+			claim.Proof = append(claim.Proof, hash.Hex())
+		}
+		distribution.Claims[record.Address.String()] = claim
+	}
+	return distribution, nil
+}
+
+func writeMerkleDistribution(records []*Record, filename string) error {
+	distribution, err := newMerkleDistribution(records)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(filename, distribution); err != nil {
+		return err
+	}
+	fmt.Printf("Merkle Root: %s\n", distribution.Root)
+	fmt.Printf("Proofs of %d claims have been written to %s\n", len(distribution.Claims), filename)
+	return nil
+}
+
+func verifyProof(rootHex string) error {
+	root, err := parseHash(rootHex)
+	if err != nil {
+		return errors.Wrap(err, "invalid root")
+	}
+	owner, err := util.ParseAddress(claimOwner)
+	if err != nil {
+		return err
+	}
+	claim := &Claim{
+		Index:  claimIndex,
+		Amount: claimAmount,
+	}
+	if claimProof != "" {
+		claim.Proof = strings.Split(claimProof, ",")
+	}
+	if proofFile != "" {
+		var distribution MerkleDistribution
+		if err := readJSON(proofFile, &distribution); err != nil {
+			return err
+		}
+		var ok bool
+		if claim, ok = distribution.Claims[owner.String()]; !ok {
+			return errors.Errorf("no claim of %s in %s", owner.String(), proofFile)
+		}
+	}
+	amount, ok := new(big.Int).SetString(claim.Amount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return errors.Errorf("invalid amount %s", claim.Amount)
+	}
+	proof := make([]common.Hash, len(claim.Proof))
+	for i, hash := range claim.Proof {
+		if proof[i], err = parseHash(hash); err != nil {
+			return errors.Wrapf(err, "invalid proof hash %s", hash)
+		}
+	}
+	leaf := util.MerkleLeaf(claim.Index, owner, amount)
+	if !util.VerifyMerkleProof(root, leaf, proof) {
+		return errors.Errorf(
+			"claim of index %d, address %s and amount %s Rau is not in merkle root %s",
+			claim.Index,
+			owner.String(),
+			claim.Amount,
+			root.Hex(),
+		)
+	}
+	fmt.Printf(
+		"claim of index %d, address %s and amount %s IOTX is valid\n",
+		claim.Index,
+		owner.String(),
+		util.FormatIOTX(amount),
+	)
+	return nil
+}
+
+func parseHash(s string) (common.Hash, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(data) != common.HashLength {
+		return common.Hash{}, errors.Errorf("%s is not a 32-byte hash", s)
+	}
+	return common.BytesToHash(data), nil
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/protogen/iotexapi"
+	"github.com/iotexproject/iotex-core/protogen/iotextypes"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// readContractGasLimit is the gas limit of read only calls
+const readContractGasLimit = 1000000
+
+// MultisendCmd groups the commands to manage a multisend contract
+var MultisendCmd = &cobra.Command{
+	Use:   "multisend",
+	Short: "Read state of and manage a multisend contract",
+}
+
+var multisendStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Read owner, limit, min tips and balance of multisend contract",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return multisendStatus(endpoint, contract)
+	},
+}
+
+var multisendSetLimitCmd = &cobra.Command{
+	Use:   "set-limit limit",
+	Short: "Build an execution to set the max number of recipients per payout",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		limit, ok := new(big.Int).SetString(args[0], 10)
+		if !ok || limit.Sign() <= 0 {
+			return errors.Errorf("invalid limit %s", args[0])
+		}
+		return buildAdminExecution("setLimit", limit)
+	},
+}
+
+var multisendSetMinTipsCmd = &cobra.Command{
+	Use:   "set-min-tips min-tips",
+	Short: "Build an execution to set the min tips in Rau per payout",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		minTips, err := parseRau("min tips", args[0])
+		if err != nil {
+			return err
+		}
+		return buildAdminExecution("setMinTips", minTips)
+	},
+}
+
+var multisendWithdrawCmd = &cobra.Command{
+	Use:   "withdraw",
+	Short: "Build an execution to withdraw the balance of contract to owner",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return buildAdminExecution("withdraw")
+	},
+}
+
+var multisendTransferOwnershipCmd = &cobra.Command{
+	Use:   "transfer-ownership new-owner",
+	Short: "Build an execution to transfer the ownership of contract",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		newOwner, err := util.ParseAddress(args[0])
+		if err != nil {
+			return err
+		}
+		if newOwner == (common.Address{}) {
+			return errors.New("cannot transfer ownership to zero address")
+		}
+		return buildAdminExecution("transferOwnership", newOwner)
+	},
+}
+
+func init() {
+	MultisendCmd.PersistentFlags().StringVar(&contract, "contract", "", "multisend contract address")
+	addEndpointFlags(MultisendCmd.PersistentFlags())
+	for _, adminCmd := range []*cobra.Command{
+		multisendSetLimitCmd,
+		multisendSetMinTipsCmd,
+		multisendWithdrawCmd,
+		multisendTransferOwnershipCmd,
+	} {
+		adminCmd.Flags().StringVar(&envelopeFile, "envelope", "", "output file of unsigned execution envelope")
+		adminCmd.Flags().StringVar(&sender, "sender", "", "owner address to read pending nonce from")
+		adminCmd.Flags().Uint64Var(&nonce, "nonce", 0, "nonce of execution, read from chain if not specified")
+		adminCmd.Flags().Uint64Var(&gasLimit, "gas-limit", 7000000, "gas limit of execution")
+		adminCmd.Flags().StringVar(&gasPrice, "gas-price", "1000000000000", "gas price of execution in Rau")
+		MultisendCmd.AddCommand(adminCmd)
+	}
+	MultisendCmd.AddCommand(multisendStatusCmd)
+}
+
+// buildAdminExecution writes an unsigned envelope calling an admin method of multisend contract, which could
+// be signed and broadcast with sign and broadcast commands
+func buildAdminExecution(method string, args ...interface{}) error {
+	bytecode, err := packAdminCall(method, args...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Method: %s\n", method)
+	fmt.Printf("Byte Code: %s\n", hex.EncodeToString(bytecode))
+	if envelopeFile == "" {
+		return nil
+	}
+	return writeEnvelope(bytecode, big.NewInt(0), big.NewInt(0))
+}
+
+// packAdminCall packs the call data of an admin method of multisend contract
+func packAdminCall(method string, args ...interface{}) ([]byte, error) {
+	multisendABI, err := abi.JSON(strings.NewReader(MultisendABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid multisend abi")
+	}
+	bytecode, err := multisendABI.Pack(method, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to pack %s", method)
+	}
+	return bytecode, nil
+}
+
+// multisendState is the state of a multisend contract
+type multisendState struct {
+	contract common.Address
+	owner    common.Address
+	limit    *big.Int
+	minTips  *big.Int
+	balance  *big.Int
+}
+
+func multisendStatus(endpoint string, contract string) error {
+	state, err := readMultisendState(endpoint, contract)
+	if err != nil {
+		return err
+	}
+	contractIoAddr, err := address.FromBytes(state.contract.Bytes())
+	if err != nil {
+		return err
+	}
+	ownerIoAddr, err := address.FromBytes(state.owner.Bytes())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Contract: %s (%s)\n", contractIoAddr.String(), state.contract.String())
+	fmt.Printf("Owner: %s (%s)\n", ownerIoAddr.String(), state.owner.String())
+	fmt.Printf("Limit: %d recipients\n", state.limit)
+	fmt.Printf("Min Tips: %s IOTX or %d Rau\n", util.FormatIOTX(state.minTips), state.minTips)
+	fmt.Printf("Balance: %s IOTX or %d Rau\n", util.FormatIOTX(state.balance), state.balance)
+	return nil
+}
+
+// readMultisendState reads the owner, limit and min tips of multisend contract, and its balance
+func readMultisendState(endpoint string, contract string) (*multisendState, error) {
+	contractAddr, err := util.ParseAddress(contract)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid contract address")
+	}
+	contractIoAddr, err := address.FromBytes(contractAddr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	multisendABI, err := abi.JSON(strings.NewReader(MultisendABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid multisend abi")
+	}
+	conn, err := dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	cli := iotexapi.NewAPIServiceClient(conn)
+	state := &multisendState{contract: contractAddr}
+	for method, out := range map[string]interface{}{
+		"owner":   &state.owner,
+		"limit":   &state.limit,
+		"minTips": &state.minTips,
+	} {
+		data, err := readContract(cli, &multisendABI, contractIoAddr.String(), method)
+		if err != nil {
+			return nil, err
+		}
+		if err := multisendABI.Unpack(out, method, data); err != nil {
+			return nil, errors.Wrapf(err, "failed to unpack result of %s", method)
+		}
+	}
+	accountResponse, err := cli.GetAccount(
+		context.Background(),
+		&iotexapi.GetAccountRequest{Address: contractIoAddr.String()},
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get account %s", contractIoAddr.String())
+	}
+	balance, ok := new(big.Int).SetString(accountResponse.AccountMeta.Balance, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid balance %s", accountResponse.AccountMeta.Balance)
+	}
+	state.balance = balance
+	return state, nil
+}
+
+// readContract calls a read only method of contract. The call is signed with a throwaway key, because the
+// api server only accepts signed actions.
+func readContract(
+	cli iotexapi.APIServiceClient,
+	contractABI *abi.ABI,
+	contract string,
+	method string,
+	args ...interface{},
+) ([]byte, error) {
+	bytecode, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to pack %s", method)
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	act, err := signCore(&iotextypes.ActionCore{
+		Version:  actionVersion,
+		Nonce:    1,
+		GasLimit: readContractGasLimit,
+		GasPrice: "0",
+		Action: &iotextypes.ActionCore_Execution{
+			Execution: &iotextypes.Execution{
+				Amount:   "0",
+				Contract: contract,
+				Data:     bytecode,
+			},
+		},
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	response, err := cli.ReadContract(context.Background(), &iotexapi.ReadContractRequest{Action: act})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s of contract %s", method, contract)
+	}
+	data, err := hex.DecodeString(response.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode result of %s", method)
+	}
+	return data, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// ownerAddress is the owner of multisend contract in chainFixture
+const ownerAddress = "0x3333333333333333333333333333333333333333"
+
+func TestMultisendAdminCalls(t *testing.T) {
+	dir, err := ioutil.TempDir("", "multisend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	prevContract, prevEnvelope, prevNonce, prevGasLimit, prevGasPrice := contract, envelopeFile, nonce, gasLimit, gasPrice
+	defer func() {
+		contract, envelopeFile, nonce, gasLimit, gasPrice = prevContract, prevEnvelope, prevNonce, prevGasLimit, prevGasPrice
+	}()
+	contract, nonce, gasLimit, gasPrice = multisendAddress, 3, 7000000, "1000000000000"
+	// the call data is the method selector followed by the arguments in 32-byte words
+	tests := []struct {
+		cmd      *cobra.Command
+		args     []string
+		expected string
+	}{
+		{
+			multisendSetLimitCmd,
+			[]string{"300"},
+			"27ea6f2b" + "000000000000000000000000000000000000000000000000000000000000012c",
+		},
+		{
+			multisendSetMinTipsCmd,
+			[]string{"10000000000000000"},
+			"a225f114" + "000000000000000000000000000000000000000000000000002386f26fc10000",
+		},
+		{
+			multisendWithdrawCmd,
+			nil,
+			"3ccfd60b",
+		},
+		{
+			multisendTransferOwnershipCmd,
+			[]string{ioAddress(t, ownerAddress)},
+			"f2fde38b" + "0000000000000000000000003333333333333333333333333333333333333333",
+		},
+	}
+	for _, test := range tests {
+		envelopeFile = filepath.Join(dir, test.cmd.Name()+".json")
+		if err := test.cmd.RunE(test.cmd, test.args); err != nil {
+			t.Fatalf("%s: %v", test.cmd.Name(), err)
+		}
+		var envelope Envelope
+		if err := readJSON(envelopeFile, &envelope); err != nil {
+			t.Fatal(err)
+		}
+		if envelope.Data != test.expected {
+			t.Errorf("%s: expecting call data %s, but got %s", test.cmd.Name(), test.expected, envelope.Data)
+		}
+		if envelope.Contract != ioAddress(t, multisendAddress) || envelope.Amount != "0" || envelope.Nonce != 3 {
+			t.Errorf("%s: unexpected envelope %+v", test.cmd.Name(), envelope)
+		}
+	}
+}
+
+func TestMultisendAdminCallsError(t *testing.T) {
+	tests := []struct {
+		cmd      *cobra.Command
+		args     []string
+		expected string
+	}{
+		{multisendSetLimitCmd, []string{"0"}, "invalid limit 0"},
+		{multisendSetLimitCmd, []string{"many"}, "invalid limit many"},
+		{multisendSetMinTipsCmd, []string{"-1"}, "min tips"},
+		{multisendTransferOwnershipCmd, []string{"0x0000000000000000000000000000000000000000"}, "zero address"},
+		{multisendTransferOwnershipCmd, []string{"io1invalid"}, "invalid io address"},
+	}
+	for _, test := range tests {
+		err := test.cmd.RunE(test.cmd, test.args)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s %v: expecting error of %s, but got %v", test.cmd.Name(), test.args, test.expected, err)
+		}
+	}
+}
+
+func TestReadMultisendState(t *testing.T) {
+	server, addr, stop := startFakeChain(t)
+	defer stop()
+	state, err := readMultisendState(addr, ioAddress(t, multisendAddress))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.contract != common.HexToAddress(multisendAddress) {
+		t.Errorf("expecting contract %s, but got %s", multisendAddress, state.contract.Hex())
+	}
+	if state.owner != common.HexToAddress(ownerAddress) {
+		t.Errorf("expecting owner %s, but got %s", ownerAddress, state.owner.Hex())
+	}
+	if state.limit.Int64() != 300 {
+		t.Errorf("expecting limit 300, but got %s", state.limit)
+	}
+	if state.minTips.String() != "10000000000000000" {
+		t.Errorf("expecting min tips 10000000000000000, but got %s", state.minTips)
+	}
+	if state.balance.Cmp(iotx(5)) != 0 {
+		t.Errorf("expecting balance %s, but got %s", iotx(5), state.balance)
+	}
+	if server.Calls("ReadContract") != 3 || server.Calls("GetAccount") != 1 {
+		t.Errorf(
+			"expecting 3 reads and 1 account, but got %d and %d",
+			server.Calls("ReadContract"),
+			server.Calls("GetAccount"),
+		)
+	}
+	// a contract of which the fixture has no reads
+	if _, err := readMultisendState(addr, tokenAddress); err == nil {
+		t.Error("expecting error of unknown contract")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	network      string
+	networksFile string
+	// numDelegates is the number of delegates producing blocks in an epoch
+	numDelegates uint64 = 24
+	// numSubEpochs is the number of blocks each delegate produces in an epoch
+	numSubEpochs uint64 = 15
+)
+
+// Network is a profile of the settings to work with an iotex network
+type Network struct {
+	Endpoint          string `yaml:"endpoint"`
+	Insecure          bool   `yaml:"insecure"`
+	Config            string `yaml:"config"`
+	MultisendContract string `yaml:"multisendContract"`
+	NumDelegates      uint64 `yaml:"numDelegates"`
+	NumSubEpochs      uint64 `yaml:"numSubEpochs"`
+}
+
+// builtinNetworks are the networks known without a networks file. Each has its own committee config, while none
+// sets the multisend contract, which is deployed by each operator and given by --contract or a networks file.
+var builtinNetworks = map[string]*Network{
+	"mainnet": {
+		Endpoint:     defaultEndpoint,
+		Config:       "committee.yaml",
+		NumDelegates: 24,
+		NumSubEpochs: 15,
+	},
+	"testnet": {
+		Endpoint:     "api.testnet.iotex.one:443",
+		Config:       "committee.testnet.yaml",
+		NumDelegates: 24,
+		NumSubEpochs: 15,
+	},
+	"local": {
+		Endpoint:     "127.0.0.1:14014",
+		Insecure:     true,
+		Config:       "committee.local.yaml",
+		NumDelegates: 24,
+		NumSubEpochs: 15,
+	},
+}
+
+// addNetworkFlags adds the flags of network profiles
+func addNetworkFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&network, "network", "", "network profile, one of mainnet, testnet, local or the ones in networks file")
+	flags.StringVar(&networksFile, "networks", "networks.yaml", "yaml file of user-defined network profiles, skipped if not exists")
+}
+
+// ApplyNetwork sets the settings of the network profile selected by --network. Settings given explicitly by
+// flags are kept.
+func ApplyNetwork(flags *pflag.FlagSet) error {
+	if flags.Lookup("network") == nil || network == "" {
+		return nil
+	}
+	networks, err := loadNetworks(networksFile)
+	if err != nil {
+		return err
+	}
+	profile, ok := networks[network]
+	if !ok {
+		var names []string
+		for name := range networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return errors.Errorf("unknown network %s, expecting one of %s", network, strings.Join(names, ","))
+	}
+	setIfNotChanged := func(name string, set func()) {
+		if flag := flags.Lookup(name); flag != nil && !flag.Changed {
+			set()
+		}
+	}
+	if profile.Endpoint != "" {
+		setIfNotChanged("endpoint", func() { endpoint = profile.Endpoint })
+	}
+	setIfNotChanged("insecure", func() { insecure = profile.Insecure })
+	if profile.Config != "" {
+		setIfNotChanged("config", func() { configPath = profile.Config })
+	}
+	if profile.MultisendContract != "" {
+		setIfNotChanged("contract", func() { contract = profile.MultisendContract })
+	}
+	if profile.NumDelegates != 0 {
+		numDelegates = profile.NumDelegates
+	}
+	if profile.NumSubEpochs != 0 {
+		numSubEpochs = profile.NumSubEpochs
+	}
+	return nil
+}
+
+// loadNetworks returns the built-in networks, overridden by the ones in the networks file if it exists
+func loadNetworks(filename string) (map[string]*Network, error) {
+	networks := make(map[string]*Network)
+	for name, profile := range builtinNetworks {
+		networks[name] = profile
+	}
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return networks, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read networks file %s", filename)
+	}
+	var file map[string]*Network
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal networks file %s", filename)
+	}
+	for name, profile := range file {
+		networks[name] = profile
+	}
+	return networks, nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iotexproject/iotex-tools/util"
+)
+
+func TestBuiltinNetworks(t *testing.T) {
+	configs := make(map[string]string)
+	for name, profile := range builtinNetworks {
+		if profile.NumDelegates == 0 || profile.NumSubEpochs == 0 {
+			t.Errorf("expecting epoch geometry of network %s, but got %+v", name, profile)
+		}
+		if other, ok := configs[profile.Config]; ok {
+			t.Errorf("expecting networks %s and %s to have their own configs, but both use %s", name, other, profile.Config)
+		}
+		configs[profile.Config] = name
+		// the committee config of a built-in profile is shipped at the root of repo
+		if _, err := util.LoadCommitteeConfig(filepath.Join("..", "..", profile.Config)); err != nil {
+			t.Errorf("expecting config %s of network %s to load, but got %v", profile.Config, name, err)
+		}
+	}
+	// the contracts not bundled are reported by validation
+	config, err := util.LoadCommitteeConfig(filepath.Join("..", "..", builtinNetworks["testnet"].Config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = util.ValidateCommitteeConfig(config)
+	if err == nil {
+		t.Fatal("expecting error of testnet config without contracts")
+	}
+	for _, field := range []string{"gravityChainStartHeight", "registerContractAddress", "stakingContractAddress"} {
+		if !strings.Contains(err.Error(), field+" is required") {
+			t.Errorf("expecting %s to be reported missing, but got %v", field, err)
+		}
+	}
+}
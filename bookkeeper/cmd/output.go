@@ -0,0 +1,133 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	formatCSV    = "csv"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
+// tableWriter writes rows of named columns in csv, json or ndjson. Only the selected columns are written, in
+// the order they are selected.
+type tableWriter struct {
+	format  string
+	columns []string
+	indices []int
+	out     io.Writer
+	csv     *csv.Writer
+	rows    int
+}
+
+// newTableWriter creates a table writer of all columns, of which the selected ones are written. All columns
+// are selected if selected is empty.
+func newTableWriter(out io.Writer, format string, columns []string, selected []string) (*tableWriter, error) {
+	if len(selected) == 0 {
+		selected = columns
+	}
+	w := &tableWriter{format: strings.ToLower(format), out: out}
+	for _, name := range selected {
+		index := -1
+		for i, column := range columns {
+			if column == name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, errors.Errorf("unknown column %s, expecting one of %s", name, strings.Join(columns, ","))
+		}
+		w.columns = append(w.columns, name)
+		w.indices = append(w.indices, index)
+	}
+	switch w.format {
+	case formatCSV:
+		w.csv = csv.NewWriter(out)
+		if err := w.csv.Write(w.columns); err != nil {
+			return nil, err
+		}
+	case formatJSON, formatNDJSON:
+	default:
+		return nil, errors.Errorf("invalid format %s, expecting csv, json or ndjson", format)
+	}
+	return w, nil
+}
+
+// Write writes a row of values of all columns
+func (w *tableWriter) Write(values ...interface{}) error {
+	defer func() { w.rows++ }()
+	if w.format == formatCSV {
+		record := make([]string, len(w.indices))
+		for i, index := range w.indices {
+			record[i] = fmt.Sprint(values[index])
+		}
+		return w.csv.Write(record)
+	}
+	var buf strings.Builder
+	buf.WriteString("{")
+	for i, index := range w.indices {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(w.columns[i])
+		if err != nil {
+			return err
+		}
+		value := values[index]
+		if v, ok := value.(*big.Int); ok {
+			// big numbers are written as strings, which could be parsed without losing precision
+			value = v.String()
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(data)
+	}
+	buf.WriteString("}")
+	if w.format == formatNDJSON {
+		_, err := io.WriteString(w.out, buf.String()+"\n")
+		return err
+	}
+	prefix := ",\n"
+	if w.rows == 0 {
+		prefix = "[\n"
+	}
+	_, err := io.WriteString(w.out, prefix+buf.String())
+	return err
+}
+
+// Close flushes the rows written
+func (w *tableWriter) Close() error {
+	switch w.format {
+	case formatCSV:
+		w.csv.Flush()
+		return w.csv.Error()
+	case formatJSON:
+		if w.rows == 0 {
+			_, err := io.WriteString(w.out, "[]\n")
+			return err
+		}
+		_, err := io.WriteString(w.out, "\n]\n")
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestTableWriter(t *testing.T) {
+	columns := []string{"name", "amount", "ok"}
+	for _, test := range []struct {
+		format   string
+		selected []string
+		expected string
+	}{
+		{formatCSV, nil, "name,amount,ok\na,1000000000000000000000,true\nb,2,false\n"},
+		{formatCSV, []string{"ok", "name"}, "ok,name\ntrue,a\nfalse,b\n"},
+		{formatJSON, []string{"amount"}, "[\n{\"amount\":\"1000000000000000000000\"},\n{\"amount\":\"2\"}\n]\n"},
+		{formatNDJSON, nil, "{\"name\":\"a\",\"amount\":\"1000000000000000000000\",\"ok\":true}\n{\"name\":\"b\",\"amount\":\"2\",\"ok\":false}\n"},
+	} {
+		var buf bytes.Buffer
+		w, err := newTableWriter(&buf, test.format, columns, test.selected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write("a", iotx(1000), true); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write("b", big.NewInt(2), false); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.expected {
+			t.Errorf("%s of %v: expecting %q, but got %q", test.format, test.selected, test.expected, buf.String())
+		}
+	}
+}
+
+func TestTableWriterEmptyJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newTableWriter(&buf, formatJSON, []string{"name"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("expecting empty array, but got %q", buf.String())
+	}
+}
+
+func TestTableWriterInvalid(t *testing.T) {
+	if _, err := newTableWriter(&bytes.Buffer{}, "xml", []string{"name"}, nil); err == nil {
+		t.Error("expecting error of invalid format")
+	}
+	if _, err := newTableWriter(&bytes.Buffer{}, formatCSV, []string{"name"}, []string{"age"}); err == nil {
+		t.Error("expecting error of unknown column")
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// argAddress is the source of recipient addresses in the first column
+	argAddress = "address"
+	// argAmount is the source of amounts in the second column
+	argAmount = "amount"
+	// argTotal is the source of the total amount
+	argTotal = "total"
+	// argMessage is the source of the message
+	argMessage = "msg"
+	// argColumnPrefix is the prefix of the source of the nth column, e.g., col:2
+	argColumnPrefix = "col:"
+	// argConstPrefix is the prefix of a constant value, e.g., const:0x0000000000000000000000000000000000000000
+	argConstPrefix = "const:"
+)
+
+var (
+	abiFile    string
+	methodName string
+	methodArgs string
+)
+
+// PayoutProfile defines the contract method a payout calls, and where each argument of the method comes from
+type PayoutProfile struct {
+	ABI    abi.ABI
+	Method string
+	Args   []string
+	// Builtin is true if the profile is the built-in multisend one
+	Builtin bool
+}
+
+func init() {
+	ConvertCmd.Flags().StringVar(&abiFile, "abi", "", "abi file of payout contract, built-in multisend if not specified")
+	ConvertCmd.Flags().StringVar(&methodName, "method", sendCoin, "method of payout contract to call")
+	ConvertCmd.Flags().StringVar(
+		&methodArgs,
+		"args",
+		strings.Join([]string{argAddress, argAmount, argMessage}, ","),
+		"comma separated sources of method arguments, each of address|amount|total|msg|col:N|const:VALUE",
+	)
+}
+
+// loadProfile loads the payout profile from the abi file, or the built-in multisend profile if no file is given
+func loadProfile(abiFile string, method string, args string) (*PayoutProfile, error) {
+	abiJSON := MultisendABI
+	if abiFile != "" {
+		data, err := ioutil.ReadFile(abiFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read abi file %s", abiFile)
+		}
+		abiJSON = string(data)
+	}
+	contractABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid abi")
+	}
+	m, ok := contractABI.Methods[method]
+	if !ok {
+		return nil, errors.Errorf("method %s is not in abi", method)
+	}
+	var sources []string
+	for _, arg := range strings.Split(args, ",") {
+		sources = append(sources, strings.TrimSpace(arg))
+	}
+	if len(sources) != len(m.Inputs) {
+		return nil, errors.Errorf("method %s has %d arguments, but %d sources are given", method, len(m.Inputs), len(sources))
+	}
+	return &PayoutProfile{
+		ABI:     contractABI,
+		Method:  method,
+		Args:    sources,
+		Builtin: abiFile == "",
+	}, nil
+}
+
+// Pack packs the call of payout method with records
+func (p *PayoutProfile) Pack(records []*Record, message string) ([]byte, error) {
+	inputs := p.ABI.Methods[p.Method].Inputs
+	values := make([]interface{}, len(inputs))
+	for i, input := range inputs {
+		value, err := argValue(input, p.Args[i], records, message)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid argument %s of method %s", input.Name, p.Method)
+		}
+		values[i] = value
+	}
+	return p.ABI.Pack(p.Method, values...)
+}
+
+func argValue(input abi.Argument, source string, records []*Record, message string) (interface{}, error) {
+	addrs, amounts, total := splitRecords(records)
+	switch {
+	case source == argAddress:
+		return addrs, nil
+	case source == argAmount:
+		return amounts, nil
+	case source == argTotal:
+		return total, nil
+	case source == argMessage:
+		return message, nil
+	case strings.HasPrefix(source, argConstPrefix):
+		return parseArg(input.Type, strings.TrimPrefix(source, argConstPrefix))
+	case strings.HasPrefix(source, argColumnPrefix):
+		column, err := strconv.Atoi(strings.TrimPrefix(source, argColumnPrefix))
+		if err != nil || column < 0 {
+			return nil, errors.Errorf("invalid column in %s", source)
+		}
+		if input.Type.T != abi.SliceTy {
+			return nil, errors.Errorf("column %d could only be passed as an array, but the type is %s", column, input.Type.String())
+		}
+		values := reflect.MakeSlice(input.Type.Type, 0, len(records))
+		for _, record := range records {
+			if column >= len(record.Fields) {
+				return nil, errors.Errorf("line %d has no column %d", record.Line, column)
+			}
+			value, err := parseArg(*input.Type.Elem, record.Fields[column])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", record.Line)
+			}
+			values = reflect.Append(values, reflect.ValueOf(value))
+		}
+		return values.Interface(), nil
+	default:
+		return nil, errors.Errorf("unknown source %s", source)
+	}
+}
+
+// parseArg parses a string into a value of the abi type
+func parseArg(t abi.Type, s string) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	switch t.T {
+	case abi.AddressTy:
+		return util.ParseAddress(s)
+	case abi.StringTy:
+		return s, nil
+	case abi.BoolTy:
+		return strconv.ParseBool(s)
+	case abi.IntTy, abi.UintTy:
+		value, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, errors.Errorf("invalid integer %s", s)
+		}
+		if t.Type == reflect.TypeOf(value) {
+			return value, nil
+		}
+		if t.T == abi.UintTy {
+			if value.Sign() < 0 || value.BitLen() > t.Size {
+				return nil, errors.Errorf("%s overflows %s", s, t.String())
+			}
+			return reflect.ValueOf(value.Uint64()).Convert(t.Type).Interface(), nil
+		}
+		if value.BitLen() >= t.Size {
+			return nil, errors.Errorf("%s overflows %s", s, t.String())
+		}
+		return reflect.ValueOf(value.Int64()).Convert(t.Type).Interface(), nil
+	default:
+		return nil, errors.Errorf("type %s is not supported", t.String())
+	}
+}
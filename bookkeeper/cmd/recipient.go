@@ -0,0 +1,163 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+)
+
+var (
+	mergeDuplicates  bool
+	checkZeroAddress bool
+	contractListFile string
+	denyListFile     string
+)
+
+func init() {
+	ConvertCmd.Flags().BoolVar(&mergeDuplicates, "merge-duplicates", false, "merge rows of the same recipient instead of failing")
+	ConvertCmd.Flags().BoolVar(&checkZeroAddress, "check-zero-address", false, "reject the zero address as recipient")
+	ConvertCmd.Flags().StringVar(&contractListFile, "contract-list", "", "file of known contract addresses to reject as recipients")
+	ConvertCmd.Flags().StringVar(&denyListFile, "deny-list", "", "file of addresses to reject as recipients")
+}
+
+// dedupRecords detects records of the same recipient, no matter whether the address is written in io or 0x
+// format. Duplicates are merged into the first record of the recipient if merge is true, otherwise an error
+// listing all of them is returned. A merged record keeps the fields of the first record, so duplicates whose
+// columns other than address and amount differ could not be merged.
+func dedupRecords(records []*Record, merge bool) ([]*Record, error) {
+	byAddr := make(map[common.Address][]*Record)
+	var addrs []common.Address
+	for _, record := range records {
+		if _, ok := byAddr[record.Address]; !ok {
+			addrs = append(addrs, record.Address)
+		}
+		byAddr[record.Address] = append(byAddr[record.Address], record)
+	}
+	if len(addrs) == len(records) {
+		return records, nil
+	}
+	var report, conflicts []string
+	var deduped []*Record
+	for _, addr := range addrs {
+		group := byAddr[addr]
+		if len(group) == 1 {
+			deduped = append(deduped, group[0])
+			continue
+		}
+		var lines []string
+		merged := &Record{
+			Line:    group[0].Line,
+			Address: addr,
+			Amount:  big.NewInt(0),
+			Exact:   new(big.Rat),
+			Fields:  append([]string(nil), group[0].Fields...),
+		}
+		for _, record := range group {
+			lines = append(lines, strconv.Itoa(record.Line))
+			merged.Amount.Add(merged.Amount, record.Amount)
+			merged.Exact.Add(merged.Exact, record.Exact)
+			if !equalStrings(extraFields(record), extraFields(group[0])) {
+				conflicts = append(conflicts, fmt.Sprintf(
+					"line %d: %s has columns %v other than address and amount, but line %d has %v",
+					record.Line,
+					addr.String(),
+					extraFields(record),
+					group[0].Line,
+					extraFields(group[0]),
+				))
+			}
+		}
+		report = append(report, fmt.Sprintf(
+			"%s appears in lines %s, %d Rau in total",
+			addr.String(),
+			strings.Join(lines, ", "),
+			merged.Amount,
+		))
+		deduped = append(deduped, merged)
+	}
+	if merge && len(conflicts) != 0 {
+		return nil, errors.Errorf(
+			"%d duplicate recipients could not be merged\n%s",
+			len(conflicts),
+			strings.Join(conflicts, "\n"),
+		)
+	}
+	if !merge {
+		return nil, errors.Errorf(
+			"%d recipients appear more than once\n%s",
+			len(report),
+			strings.Join(report, "\n"),
+		)
+	}
+	fmt.Println("Merged duplicate recipients:")
+	for _, line := range report {
+		fmt.Printf("\t%s\n", line)
+	}
+	return deduped, nil
+}
+
+// extraFields returns the columns of a record other than address and amount
+func extraFields(record *Record) []string {
+	if len(record.Fields) <= 2 {
+		return nil
+	}
+	return record.Fields[2:]
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// screenRecords rejects recipients which are the zero address, known contracts or on the deny list
+func screenRecords(records []*Record, zeroAddress bool, contractList string, denyList string) error {
+	var contracts, denied map[common.Address]bool
+	var err error
+	if contractList != "" {
+		if contracts, err = util.ReadAddressList(contractList); err != nil {
+			return err
+		}
+	}
+	if denyList != "" {
+		if denied, err = util.ReadAddressList(denyList); err != nil {
+			return err
+		}
+	}
+	var flagged []string
+	for _, record := range records {
+		var reason string
+		switch {
+		case zeroAddress && record.Address == (common.Address{}):
+			reason = "zero address"
+		case contracts[record.Address]:
+			reason = "known contract address"
+		case denied[record.Address]:
+			reason = "address on deny list"
+		default:
+			continue
+		}
+		flagged = append(flagged, fmt.Sprintf("line %d: %s is %s", record.Line, record.Address.String(), reason))
+	}
+	if len(flagged) != 0 {
+		return errors.Errorf("%d recipients are rejected\n%s", len(flagged), strings.Join(flagged, "\n"))
+	}
+	return nil
+}
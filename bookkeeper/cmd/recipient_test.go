@@ -0,0 +1,134 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iotexproject/iotex-tools/util"
+)
+
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// newRecords parses the rows of amounts in Rau into records, numbering lines from 1
+func newRecords(t *testing.T, rows ...[]string) []*Record {
+	t.Helper()
+	var records []*Record
+	for i, row := range rows {
+		record, err := parseRecord(row, 0, util.RoundNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record.Line = i + 1
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestDedupRecords(t *testing.T) {
+	ioA := ioAddress(t, "0x"+voterA)
+	for _, test := range []struct {
+		reason string
+		rows   [][]string
+		merge  bool
+		// amounts are the amounts of deduped records, or nil if an error is expected
+		amounts []string
+		// fields are the extra columns of the first deduped record
+		fields []string
+	}{
+		{"no duplicate", [][]string{{"0x" + voterA, "1"}, {"0x" + voterB, "2"}}, false, []string{"1", "2"}, nil},
+		{"duplicate without merge", [][]string{{"0x" + voterA, "1"}, {"0x" + voterB, "2"}, {ioA, "3"}}, false, nil, nil},
+		{"duplicate merged", [][]string{{"0x" + voterA, "1"}, {"0x" + voterB, "2"}, {ioA, "3"}}, true, []string{"4", "2"}, nil},
+		{
+			"duplicate merged with the same extra columns",
+			[][]string{{"0x" + voterA, "1", "7"}, {ioA, "3", "7"}},
+			true,
+			[]string{"4"},
+			[]string{"7"},
+		},
+		{
+			"duplicate with different extra columns",
+			[][]string{{"0x" + voterA, "1", "7"}, {ioA, "3", "8"}},
+			true,
+			nil,
+			nil,
+		},
+	} {
+		deduped, err := dedupRecords(newRecords(t, test.rows...), test.merge)
+		if test.amounts == nil {
+			if err == nil {
+				t.Errorf("expecting error of %s", test.reason)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expecting no error of %s, but got %v", test.reason, err)
+			continue
+		}
+		var amounts []string
+		for _, record := range deduped {
+			amounts = append(amounts, record.Amount.String())
+		}
+		if strings.Join(amounts, ",") != strings.Join(test.amounts, ",") {
+			t.Errorf("expecting amounts %v of %s, but got %v", test.amounts, test.reason, amounts)
+		}
+		if extra := extraFields(deduped[0]); strings.Join(extra, ",") != strings.Join(test.fields, ",") {
+			t.Errorf("expecting extra columns %v of %s, but got %v", test.fields, test.reason, extra)
+		}
+		if deduped[0].Line != 1 || deduped[0].Exact.Cmp(new(big.Rat).SetInt(deduped[0].Amount)) != 0 {
+			t.Errorf("unexpected record %+v of %s", deduped[0], test.reason)
+		}
+	}
+}
+
+func TestScreenRecords(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recipient")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	contractList := filepath.Join(dir, "contracts.txt")
+	if err := ioutil.WriteFile(contractList, []byte("# token\n"+ioAddress(t, tokenAddress)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	denyList := filepath.Join(dir, "deny.txt")
+	if err := ioutil.WriteFile(denyList, []byte("0x"+voterC+" # exchange\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		reason       string
+		addr         string
+		zeroAddress  bool
+		contractList string
+		denyList     string
+		rejected     bool
+	}{
+		{"ordinary recipient", "0x" + voterA, true, contractList, denyList, false},
+		{"zero address", zeroAddress, true, "", "", true},
+		{"zero address not checked", zeroAddress, false, "", "", false},
+		{"known contract", tokenAddress, false, contractList, "", true},
+		{"contract without list", tokenAddress, false, "", denyList, false},
+		{"denied address", "0x" + voterC, false, "", denyList, true},
+	} {
+		records := newRecords(t, []string{"0x" + voterB, "1"}, []string{test.addr, "1"})
+		err := screenRecords(records, test.zeroAddress, test.contractList, test.denyList)
+		if test.rejected && (err == nil || !strings.Contains(err.Error(), "line 2:")) {
+			t.Errorf("expecting line 2 of %s to be rejected, but got %v", test.reason, err)
+		}
+		if !test.rejected && err != nil {
+			t.Errorf("expecting %s to pass, but got %v", test.reason, err)
+		}
+	}
+	if err := screenRecords(newRecords(t, []string{"0x" + voterA, "1"}), false, filepath.Join(dir, "missing.txt"), ""); err == nil {
+		t.Error("expecting error of missing contract list")
+	}
+}
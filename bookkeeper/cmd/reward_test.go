@@ -0,0 +1,246 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/protogen/iotexapi"
+	"github.com/iotexproject/iotex-tools/test/fake"
+	"github.com/iotexproject/iotex-tools/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chainFixture has the last blocks of epochs 1 to 8, at the gravity chain heights of committeeFixture. Epochs 1
+// and 2 grant iotexlab 200 IOTX epoch reward and 30 IOTX foundation bonus, epoch 3 ends with a transfer, epoch 4
+// grants robotbp only, epoch 5 has no last block, the last block of epoch 6 has no action, epoch 7 is at the
+// height where iotexlab has no reward address and has no last block, and epoch 8 grants iotexlab 200 IOTX epoch
+// reward. Multisend contract 0x2222222222222222222222222222222222222222 is owned by
+// 0x3333333333333333333333333333333333333333, with limit 300, min tips 0.01 IOTX and balance 5 IOTX.
+const chainFixture = "testdata/chain.json"
+
+// startFakeChain starts a fake iotex api server without TLS, and returns it, its address and the function to stop it
+func startFakeChain(t *testing.T) (*fake.Server, string, func()) {
+	t.Helper()
+	server, err := fake.NewServerFromFile(chainFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := server.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := insecure
+	insecure = true
+	return server, addr, func() {
+		insecure = prev
+		server.Stop()
+	}
+}
+
+func TestGravityChainHeight(t *testing.T) {
+	_, addr, stop := startFakeChain(t)
+	defer stop()
+	height, err := gravityChainHeight(addr, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 200 {
+		t.Errorf("expecting gravity chain height 200, but got %d", height)
+	}
+	if _, err := gravityChainHeight(addr, 9); err == nil {
+		t.Error("expecting error of unknown epoch")
+	}
+}
+
+func TestFakeChainUnimplemented(t *testing.T) {
+	server, addr, stop := startFakeChain(t)
+	defer stop()
+	conn, err := dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	cli := iotexapi.NewAPIServiceClient(conn)
+	_, err = cli.SuggestGasPrice(context.Background(), &iotexapi.SuggestGasPriceRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expecting code %s, but got %v", codes.Unimplemented, err)
+	}
+	if server.Calls("SuggestGasPrice") != 1 {
+		t.Errorf("expecting 1 call of SuggestGasPrice, but got %d", server.Calls("SuggestGasPrice"))
+	}
+}
+
+func TestGetReward(t *testing.T) {
+	_, addr, stop := startFakeChain(t)
+	defer stop()
+	for _, test := range []struct {
+		epoch               uint64
+		rewardAddress       string
+		withFoundationBonus bool
+		reward              *big.Int
+	}{
+		// block rewards are never counted
+		{1, "io1rewardiotexlab", false, iotx(200)},
+		{1, "io1rewardiotexlab", true, iotx(230)},
+		{1, "io1rewardrobotbp", true, iotx(130)},
+		{2, "io1rewardrobotbp", true, iotx(100)},
+		{4, "io1rewardiotexlab", true, big.NewInt(0)},
+		{4, "io1unknown", false, big.NewInt(0)},
+	} {
+		reward, err := getReward(addr, test.epoch, test.rewardAddress, test.withFoundationBonus)
+		if err != nil {
+			t.Fatalf("failed to get reward of %s in epoch %d: %v", test.rewardAddress, test.epoch, err)
+		}
+		if reward.Cmp(test.reward) != 0 {
+			t.Errorf(
+				"expecting reward %d of %s in epoch %d with foundation bonus %t, but got %d",
+				test.reward,
+				test.rewardAddress,
+				test.epoch,
+				test.withFoundationBonus,
+				reward,
+			)
+		}
+	}
+}
+
+func TestGetRewardError(t *testing.T) {
+	server, addr, stop := startFakeChain(t)
+	defer stop()
+	for _, test := range []struct {
+		epoch  uint64
+		reason string
+	}{
+		{3, "last action not grant reward"},
+		{5, "missing last block"},
+		{6, "last block without action"},
+	} {
+		if _, err := getReward(addr, test.epoch, "io1rewardiotexlab", true); err == nil {
+			t.Errorf("expecting error of %s in epoch %d", test.reason, test.epoch)
+		}
+	}
+	if server.Calls("GetReceiptByAction") != 0 {
+		t.Errorf("expecting no receipt fetched, but got %d", server.Calls("GetReceiptByAction"))
+	}
+}
+
+// exportFixtures runs export of iotexlab offline against the fake chain and the snapshots of committeeFixture in a
+// temp dir, and returns the rows of the csv written there
+func exportFixtures(t *testing.T, startEpoch, toEpoch uint64, withFoundationBonus, useIOAddr bool) ([][]string, error) {
+	t.Helper()
+	_, addr, stop := startFakeChain(t)
+	defer stop()
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	archive, err := util.NewSnapshotCommittee(dir, newFakeCommittee(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, height := range []uint64{100, 200} {
+		if _, err := archive.FetchResultByHeight(height); err != nil {
+			t.Fatal(err)
+		}
+	}
+	committee, err := util.NewSnapshotCommittee(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := export(committee, "iotexlab", startEpoch, toEpoch, addr, "Rau", 90, withFoundationBonus, useIOAddr, dir); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filepath.Join(dir, fmt.Sprintf("iotexlab_epoch_%d_to_%d_in_Rau.csv", startEpoch, toEpoch)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows, nil
+}
+
+func TestExport(t *testing.T) {
+	rows, err := exportFixtures(t, 1, 2, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 90% of 230 IOTX in each epoch, as in TestDistribute, sorted by reward
+	expected := [][]string{
+		{"0x" + voterB, iotx(318).String()},
+		{"0x" + voterA, iotx(69).String()},
+		{"0x" + voterD, iotx(27).String()},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("expecting %d rows, but got %v", len(expected), rows)
+	}
+	for i, row := range rows {
+		if !strings.EqualFold(row[0], expected[i][0]) || row[1] != expected[i][1] {
+			t.Errorf("expecting row %d to be %v, but got %v", i, expected[i], row)
+		}
+	}
+}
+
+func TestExportWithoutFoundationBonus(t *testing.T) {
+	rows, err := exportFixtures(t, 1, 1, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 90% of 200 IOTX, of which B has 2/3 and A has 1/3
+	voter, err := hex.DecodeString(voterB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioAddr, err := address.FromBytes(voter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0][0] != ioAddr.String() || rows[0][1] != iotx(120).String() || rows[1][1] != iotx(60).String() {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+func TestExportZeroReward(t *testing.T) {
+	rows, err := exportFixtures(t, 4, 4, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("expecting nothing distributed without reward, but got %v", rows)
+	}
+}
+
+func TestExportError(t *testing.T) {
+	for _, test := range []struct {
+		startEpoch uint64
+		toEpoch    uint64
+		reason     string
+	}{
+		{2, 3, "last action not grant reward"},
+		{5, 5, "missing last block"},
+		{9, 9, "unknown epoch"},
+	} {
+		if _, err := exportFixtures(t, test.startEpoch, test.toEpoch, true, false); err == nil {
+			t.Errorf("expecting error of %s from epoch %d to %d", test.reason, test.startEpoch, test.toEpoch)
+		}
+	}
+}
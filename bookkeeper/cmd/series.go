@@ -0,0 +1,140 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var concurrency uint
+
+// seriesColumns are the columns of time series
+var seriesColumns = []string{
+	"epoch",
+	"height",
+	"delegate",
+	"votes",
+	"tokens",
+	"voters",
+	"rank",
+}
+
+// epochSummary is the summary of delegates at an epoch
+type epochSummary struct {
+	height    uint64
+	summaries []*DelegateSummary
+}
+
+// RangeCmd writes the time series of delegate votes across an epoch range
+var RangeCmd = &cobra.Command{
+	Use:   "range",
+	Short: "Write the time series of delegate votes across an epoch range",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		filter, err := newVoteFilter(delegates, nil, "0")
+		if err != nil {
+			return err
+		}
+		writer, err := newTableWriter(os.Stdout, outputFormat, seriesColumns, columns)
+		if err != nil {
+			return err
+		}
+		return voteSeries(configPath, endpoint, fromEpoch, toEpoch, concurrency, filter, writer)
+	},
+}
+
+func init() {
+	DumpCmd.AddCommand(RangeCmd)
+	RangeCmd.Flags().Uint64Var(&fromEpoch, "from-epoch", 0, "first iotex epoch of the range")
+	RangeCmd.Flags().Uint64Var(&toEpoch, "to-epoch", 0, "last iotex epoch of the range")
+	RangeCmd.Flags().UintVar(&concurrency, "concurrency", 4, "max number of epochs to fetch at the same time")
+	RangeCmd.Flags().StringSliceVar(&delegates, "delegate", nil, "only write the delegates")
+}
+
+func voteSeries(
+	configPath string,
+	endpoint string,
+	fromEpoch uint64,
+	toEpoch uint64,
+	concurrency uint,
+	filter *voteFilter,
+	writer *tableWriter,
+) error {
+	if fromEpoch == 0 || toEpoch < fromEpoch {
+		return errors.Errorf("invalid epoch range from %d to %d", fromEpoch, toEpoch)
+	}
+	if concurrency == 0 {
+		return errors.New("concurrency should be positive")
+	}
+	// snapshots are keyed by gravity chain height, so the height of each epoch could only be read from the
+	// iotex endpoint
+	if offline {
+		return errors.New("range could not run with --offline, as the gravity chain heights of epochs are read from the iotex endpoint")
+	}
+	committee, err := newCommittee(configPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create committee")
+	}
+	epochs := make([]*epochSummary, toEpoch-fromEpoch+1)
+	errs := make([]error, len(epochs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range epochs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			epochNum := fromEpoch + uint64(i)
+			height, err := gravityChainHeight(endpoint, epochNum)
+			if err != nil {
+				errs[i] = errors.Wrapf(err, "failed to get gravity chain height for epoch %d", epochNum)
+				return
+			}
+			result, err := committee.FetchResultByHeight(height)
+			if err != nil {
+				errs[i] = errors.Wrapf(err, "failed to fetch result of epoch %d at height %d", epochNum, height)
+				return
+			}
+			zap.L().Info("Fetched result", zap.Uint64("epoch", epochNum), zap.Uint64("height", height))
+			epochs[i] = &epochSummary{height: height, summaries: summarizeDelegates(result)}
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	for i, epoch := range epochs {
+		for _, summary := range epoch.summaries {
+			if !filter.matchDelegate(summary.name) {
+				continue
+			}
+			if err := writer.Write(
+				fromEpoch+uint64(i),
+				epoch.height,
+				summary.Name,
+				summary.Votes,
+				summary.Tokens,
+				summary.Voters,
+				summary.Rank,
+			); err != nil {
+				return errors.Wrap(err, "error writing series")
+			}
+		}
+	}
+	return writer.Close()
+}
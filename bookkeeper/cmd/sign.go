@@ -0,0 +1,133 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	keystoreFile string
+	passwordFile string
+	signedFile   string
+)
+
+// SignCmd signs an envelope offline
+var SignCmd = &cobra.Command{
+	Use:   "sign envelope-file",
+	Short: "Sign an unsigned envelope offline with a keystore",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		signed, err := sign(args[0], keystoreFile, passwordFile)
+		if err != nil {
+			return err
+		}
+		if err := writeJSON(signedFile, signed); err != nil {
+			return err
+		}
+		fmt.Printf("Action %s signed by %s has been written to %s\n", signed.Hash, signed.Sender, signedFile)
+		return nil
+	},
+}
+
+func init() {
+	SignCmd.Flags().StringVar(&keystoreFile, "keystore", "", "keystore file of the payout key")
+	SignCmd.Flags().StringVar(&passwordFile, "password-file", "", "file of the keystore password, prompt if not specified")
+	SignCmd.Flags().StringVarP(&signedFile, "output", "o", "signed.json", "output file of signed action")
+}
+
+func sign(envelopeFile string, keystoreFile string, passwordFile string) (*SignedAction, error) {
+	var envelope Envelope
+	if err := readJSON(envelopeFile, &envelope); err != nil {
+		return nil, err
+	}
+	if _, err := envelope.Core(); err != nil {
+		return nil, errors.Wrapf(err, "invalid envelope %s", envelopeFile)
+	}
+	fmt.Printf("Contract: %s\n", envelope.Contract)
+	fmt.Printf("Amount: %s Rau (payout total %s, tips %s Rau)\n", envelope.Amount, envelope.Total, envelope.Tips)
+	fmt.Printf("Nonce: %d, Gas Limit: %d, Gas Price: %s Rau\n", envelope.Nonce, envelope.GasLimit, envelope.GasPrice)
+	if keystoreFile == "" {
+		return nil, errors.New("keystore is not specified")
+	}
+	keyJSON, err := ioutil.ReadFile(keystoreFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read keystore %s", keystoreFile)
+	}
+	password, err := readPassword(passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt keystore")
+	}
+	return signEnvelope(&envelope, key.PrivateKey)
+}
+
+// signEnvelope signs the action core of the envelope with the private key, and verifies the signed action
+func signEnvelope(envelope *Envelope, key *ecdsa.PrivateKey) (*SignedAction, error) {
+	core, err := envelope.Core()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid envelope")
+	}
+	act, err := signCore(core, key)
+	if err != nil {
+		return nil, err
+	}
+	actBytes, err := proto.Marshal(act)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := address.FromBytes(crypto.PubkeyToAddress(key.PublicKey).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	actHash := blake2b.Sum256(actBytes)
+	signed := &SignedAction{
+		Envelope: *envelope,
+		Sender:   sender.String(),
+		Hash:     hex.EncodeToString(actHash[:]),
+		Action:   hex.EncodeToString(actBytes),
+	}
+	if _, err := signed.Verify(); err != nil {
+		return nil, errors.Wrap(err, "failed to verify signed action")
+	}
+	return signed, nil
+}
+
+func readPassword(passwordFile string) (string, error) {
+	if passwordFile != "" {
+		password, err := ioutil.ReadFile(passwordFile)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read password file %s", passwordFile)
+		}
+		return strings.TrimRight(string(password), "\r\n"), nil
+	}
+	fmt.Print("Enter password of keystore: ")
+	password, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read password")
+	}
+	return string(password), nil
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/protogen/iotextypes"
+	"golang.org/x/crypto/blake2b"
+)
+
+// payoutEnvelope returns the envelope of the payout of payoutRecords with 1 Rau tips
+func payoutEnvelope(t *testing.T) *Envelope {
+	t.Helper()
+	contractAddr, err := address.FromBytes(common.HexToAddress(tokenAddress).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Envelope{
+		Contract: contractAddr.String(),
+		Amount:   new(big.Int).Add(iotx(3), big.NewInt(1)).String(),
+		Total:    iotx(3).String(),
+		Tips:     "1",
+		Nonce:    7,
+		GasLimit: 7000000,
+		GasPrice: "1000000000000",
+		Data:     hex.EncodeToString(packPayout(t, sendCoin, "address,amount,msg")),
+	}
+}
+
+func TestSignEnvelope(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := payoutEnvelope(t)
+	signed, err := signEnvelope(envelope, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	act, err := signed.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := address.FromBytes(crypto.PubkeyToAddress(key.PublicKey).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.Sender != sender.String() {
+		t.Errorf("expecting sender %s, but got %s", sender.String(), signed.Sender)
+	}
+	execution := act.Core.GetExecution()
+	if execution == nil {
+		t.Fatal("expecting an execution")
+	}
+	if execution.Amount != envelope.Amount || execution.Contract != envelope.Contract || act.Core.Nonce != envelope.Nonce {
+		t.Errorf("signed execution %+v does not match envelope %+v", execution, envelope)
+	}
+	if hex.EncodeToString(execution.Data) != envelope.Data {
+		t.Errorf("expecting data %s, but got %x", envelope.Data, execution.Data)
+	}
+}
+
+func TestVerifyTamperedAction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := signEnvelope(payoutEnvelope(t), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSigned, err := signEnvelope(payoutEnvelope(t), other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// tamperAction modifies the signed action, and reencodes it with a matching hash
+	tamperAction := func(s *SignedAction, tamper func(*iotextypes.Action)) {
+		actBytes, err := hex.DecodeString(s.Action)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var act iotextypes.Action
+		if err := proto.Unmarshal(actBytes, &act); err != nil {
+			t.Fatal(err)
+		}
+		tamper(&act)
+		if actBytes, err = proto.Marshal(&act); err != nil {
+			t.Fatal(err)
+		}
+		actHash := blake2b.Sum256(actBytes)
+		s.Action = hex.EncodeToString(actBytes)
+		s.Hash = hex.EncodeToString(actHash[:])
+	}
+	for _, test := range []struct {
+		reason string
+		tamper func(*SignedAction)
+	}{
+		{"more amount in envelope", func(s *SignedAction) {
+			s.Envelope.Tips = "2"
+			s.Envelope.Amount = new(big.Int).Add(iotx(3), big.NewInt(2)).String()
+		}},
+		{"another nonce in envelope", func(s *SignedAction) { s.Envelope.Nonce++ }},
+		{"another sender", func(s *SignedAction) { s.Sender = otherSigned.Sender }},
+		{"another hash", func(s *SignedAction) { s.Hash = otherSigned.Hash }},
+		{"action signed by another key", func(s *SignedAction) {
+			s.Action, s.Hash = otherSigned.Action, otherSigned.Hash
+		}},
+		{"more amount in action", func(s *SignedAction) {
+			tamperAction(s, func(act *iotextypes.Action) { act.Core.GetExecution().Amount = iotx(4).String() })
+		}},
+		{"another gas price in action", func(s *SignedAction) {
+			tamperAction(s, func(act *iotextypes.Action) { act.Core.GasPrice = "1" })
+		}},
+		{"flipped signature", func(s *SignedAction) {
+			tamperAction(s, func(act *iotextypes.Action) { act.Signature[10] ^= 0xff })
+		}},
+		{"public key of another key", func(s *SignedAction) {
+			tamperAction(s, func(act *iotextypes.Action) { act.SenderPubKey = crypto.FromECDSAPub(&other.PublicKey) })
+		}},
+		{"truncated signature", func(s *SignedAction) {
+			tamperAction(s, func(act *iotextypes.Action) { act.Signature = act.Signature[:64] })
+		}},
+		{"invalid action", func(s *SignedAction) { s.Action = "zz" }},
+	} {
+		tampered := *signed
+		test.tamper(&tampered)
+		if _, err := tampered.Verify(); err == nil {
+			t.Errorf("expecting error of %s", test.reason)
+		}
+	}
+	if _, err := signed.Verify(); err != nil {
+		t.Errorf("expecting the original action to be valid, but got %v", err)
+	}
+}
+
+func TestEnvelopeCore(t *testing.T) {
+	multisendABI, err := abi.JSON(strings.NewReader(MultisendABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setLimit, err := multisendABI.Pack("setLimit", big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := payoutEnvelope(t).Core(); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		reason string
+		tamper func(*Envelope)
+	}{
+		{"amount other than total plus tips", func(e *Envelope) { e.Amount = e.Total }},
+		{"total other than the payout", func(e *Envelope) {
+			e.Total = iotx(4).String()
+			e.Amount = new(big.Int).Add(iotx(4), big.NewInt(1)).String()
+		}},
+		{"admin call sending amount", func(e *Envelope) { e.Data = hex.EncodeToString(setLimit) }},
+		{"invalid contract", func(e *Envelope) { e.Contract = "io1invalid" }},
+		{"negative tips", func(e *Envelope) { e.Tips = "-1" }},
+		{"no nonce", func(e *Envelope) { e.Nonce = 0 }},
+		{"no gas limit", func(e *Envelope) { e.GasLimit = 0 }},
+		{"unknown method", func(e *Envelope) { e.Data = "01020304" }},
+	} {
+		envelope := payoutEnvelope(t)
+		test.tamper(envelope)
+		if _, err := envelope.Core(); err == nil {
+			t.Errorf("expecting error of %s", test.reason)
+		}
+	}
+}
+
+func TestEnvelopeAmount(t *testing.T) {
+	tokenArgs := "const:" + tokenAddress + ",address,amount,msg"
+	for _, test := range []struct {
+		method string
+		args   string
+		amount *big.Int
+		wrong  *big.Int
+	}{
+		// sendCoin sends the total plus tips
+		{sendCoin, "address,amount,msg", new(big.Int).Add(iotx(3), big.NewInt(1)), big.NewInt(1)},
+		// sendToken pays the total in the token, and sends only tips
+		{sendToken, tokenArgs, big.NewInt(1), new(big.Int).Add(iotx(3), big.NewInt(1))},
+	} {
+		data := packPayout(t, test.method, test.args)
+		value, err := checkEnvelopeData(data, iotx(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		envelope := payoutEnvelope(t)
+		envelope.Data = hex.EncodeToString(data)
+		envelope.Amount = new(big.Int).Add(value, big.NewInt(1)).String()
+		if envelope.Amount != test.amount.String() {
+			t.Errorf("expecting amount %d of %s, but got %s", test.amount, test.method, envelope.Amount)
+		}
+		if _, err := envelope.Core(); err != nil {
+			t.Errorf("expecting envelope of %s to be valid, but got %v", test.method, err)
+		}
+		envelope.Amount = test.wrong.String()
+		if _, err := envelope.Core(); err == nil {
+			t.Errorf("expecting error of amount %d of %s", test.wrong, test.method)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// weightingWeighted distributes by weighted votes, as export does
+	weightingWeighted = "weighted"
+	// weightingRaw distributes by raw tokens
+	weightingRaw = "raw"
+)
+
+var (
+	scenarioFile   string
+	simulateOutput string
+)
+
+// Scenario is a candidate distribution policy
+type Scenario struct {
+	Name string `yaml:"name"`
+	// Percentage is the percentage of reward to distribute
+	Percentage uint `yaml:"percentage"`
+	// Weighting is weighted or raw, weighted if not specified
+	Weighting string `yaml:"weighting"`
+	// Tiers override the percentage by the votes of voter in an epoch
+	Tiers []Tier `yaml:"tiers"`
+	// MaxPayout is the cap of payout to a voter over all epochs in IOTX, no cap if empty
+	MaxPayout string `yaml:"maxPayout"`
+	// MinPayout is the threshold of payout in IOTX, below which a voter is not paid
+	MinPayout string `yaml:"minPayout"`
+
+	maxPayout *big.Int
+	minPayout *big.Int
+	tiers     []*tier
+}
+
+// Tier is the percentage of reward to distribute to voters with at least the votes in IOTX in an epoch
+type Tier struct {
+	MinVotes   string `yaml:"minVotes"`
+	Percentage uint   `yaml:"percentage"`
+}
+
+type tier struct {
+	minVotes   *big.Int
+	percentage uint
+}
+
+// ScenarioResult is the payouts of a scenario
+type ScenarioResult struct {
+	Payouts map[string]*big.Int
+	// BelowThreshold is the number of voters not paid for their payouts below threshold
+	BelowThreshold int
+	// Withheld is the amount not paid for caps and thresholds
+	Withheld *big.Int
+}
+
+// epochVotes is the reward and buckets of a delegate in an epoch
+type epochVotes struct {
+	reward  *big.Int
+	buckets []Bucket
+}
+
+// SimulateCmd simulates distribution policies over historical epochs
+var SimulateCmd = &cobra.Command{
+	Use:   "simulate bp-name",
+	Short: "Simulate distribution policies over historical epochs",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		scenarios, err := readScenarios(scenarioFile)
+		if err != nil {
+			return err
+		}
+		return simulate(configPath, endpoint, args[0], start, to, withFoundationBonus, scenarios, simulateOutput)
+	},
+}
+
+func init() {
+	addConfigFlag(SimulateCmd.Flags())
+	addEndpointFlags(SimulateCmd.Flags())
+	addSnapshotFlags(SimulateCmd.Flags())
+	SimulateCmd.Flags().Uint64Var(&start, "start", 0, "start epoch number")
+	SimulateCmd.Flags().Uint64Var(&to, "to", 0, "to epoch number")
+	SimulateCmd.Flags().BoolVarP(&withFoundationBonus, "with-foundation-bonus", "w", false, "epoch bonus with foundation bonus")
+	SimulateCmd.Flags().StringVar(&scenarioFile, "scenarios", "scenarios.yaml", "yaml file of scenarios, the first of which is the current policy")
+	SimulateCmd.Flags().StringVarP(&simulateOutput, "output", "o", "simulation.csv", "csv file of payouts to each voter under all scenarios")
+}
+
+// readScenarios reads and validates the scenarios in yaml
+func readScenarios(filename string) ([]*Scenario, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read scenario file %s", filename)
+	}
+	var file struct {
+		Scenarios []*Scenario `yaml:"scenarios"`
+	}
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal scenarios")
+	}
+	if len(file.Scenarios) == 0 {
+		return nil, errors.Errorf("no scenario in %s", filename)
+	}
+	for i, scenario := range file.Scenarios {
+		if scenario.Name == "" {
+			scenario.Name = fmt.Sprintf("scenario%d", i)
+		}
+		if err := scenario.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid scenario %s", scenario.Name)
+		}
+	}
+	// scenario names are columns of output, so they could neither be reserved nor duplicate
+	columns := make(map[string]bool)
+	for _, column := range simulationColumns(file.Scenarios) {
+		if columns[column] {
+			return nil, errors.Errorf("scenario name %s is reserved or duplicate", column)
+		}
+		columns[column] = true
+	}
+	return file.Scenarios, nil
+}
+
+// simulationColumns returns the columns of simulation output, which are the voter, the payout under each scenario
+// and the change of each scenario against the first one
+func simulationColumns(scenarios []*Scenario) []string {
+	columns := []string{"voter"}
+	for _, scenario := range scenarios {
+		columns = append(columns, scenario.Name)
+	}
+	for _, scenario := range scenarios[1:] {
+		columns = append(columns, scenario.Name+"Delta")
+	}
+	return columns
+}
+
+func (s *Scenario) validate() error {
+	switch strings.ToLower(s.Weighting) {
+	case "", weightingWeighted:
+		s.Weighting = weightingWeighted
+	case weightingRaw:
+		s.Weighting = weightingRaw
+	default:
+		return errors.Errorf("invalid weighting %s, expecting weighted or raw", s.Weighting)
+	}
+	if s.Percentage == 0 && len(s.Tiers) == 0 {
+		return errors.New("either percentage or tiers is required")
+	}
+	var err error
+	if s.MaxPayout != "" {
+		if s.maxPayout, _, err = util.ParseAmount(s.MaxPayout, util.IOTXDecimals, util.RoundNone); err != nil {
+			return errors.Wrap(err, "invalid max payout")
+		}
+	}
+	s.minPayout = big.NewInt(0)
+	if s.MinPayout != "" {
+		if s.minPayout, _, err = util.ParseAmount(s.MinPayout, util.IOTXDecimals, util.RoundNone); err != nil {
+			return errors.Wrap(err, "invalid min payout")
+		}
+	}
+	for _, t := range s.Tiers {
+		minVotes := big.NewInt(0)
+		if t.MinVotes != "" {
+			if minVotes, _, err = util.ParseAmount(t.MinVotes, util.IOTXDecimals, util.RoundNone); err != nil {
+				return errors.Wrap(err, "invalid min votes of tier")
+			}
+		}
+		s.tiers = append(s.tiers, &tier{minVotes: minVotes, percentage: t.Percentage})
+	}
+	// tiers are matched from the highest min votes
+	sort.SliceStable(s.tiers, func(i, j int) bool {
+		return s.tiers[i].minVotes.Cmp(s.tiers[j].minVotes) > 0
+	})
+	return nil
+}
+
+// percentageOf returns the percentage of reward to distribute to a voter with the votes
+func (s *Scenario) percentageOf(votes *big.Int) uint {
+	for _, t := range s.tiers {
+		if votes.Cmp(t.minVotes) >= 0 {
+			return t.percentage
+		}
+	}
+	return s.Percentage
+}
+
+// run distributes the rewards of epochs under the scenario, as export does
+func (s *Scenario) run(epochs []*epochVotes) *ScenarioResult {
+	payouts := make(map[string]*big.Int)
+	for _, epoch := range epochs {
+		buckets := s.bucketsOf(epoch)
+		total := big.NewInt(0)
+		votes := make(map[string]*big.Int)
+		for _, bucket := range buckets {
+			total.Add(total, bucket.amount)
+			if _, ok := votes[bucket.owner]; !ok {
+				votes[bucket.owner] = big.NewInt(0)
+			}
+			votes[bucket.owner].Add(votes[bucket.owner], bucket.amount)
+		}
+		if total.Sign() == 0 {
+			continue
+		}
+		// buckets are distributed in groups of the percentage of their owners
+		groups := make(map[uint][]Bucket)
+		for _, bucket := range buckets {
+			pct := s.percentageOf(votes[bucket.owner])
+			groups[pct] = append(groups[pct], bucket)
+		}
+		for pct, group := range groups {
+			distribute(payouts, group, epoch.reward, total, pct)
+		}
+	}
+	result := &ScenarioResult{Payouts: payouts, Withheld: big.NewInt(0)}
+	for _, payout := range payouts {
+		if s.maxPayout != nil && payout.Cmp(s.maxPayout) > 0 {
+			result.Withheld.Add(result.Withheld, new(big.Int).Sub(payout, s.maxPayout))
+			payout.Set(s.maxPayout)
+		}
+		if payout.Sign() > 0 && payout.Cmp(s.minPayout) < 0 {
+			result.BelowThreshold++
+			result.Withheld.Add(result.Withheld, payout)
+			payout.SetInt64(0)
+		}
+	}
+	return result
+}
+
+// payoutOf returns the payout to a voter, which is zero if the voter is not paid at all
+func (r *ScenarioResult) payoutOf(voter string) *big.Int {
+	if payout, ok := r.Payouts[voter]; ok {
+		return payout
+	}
+	return big.NewInt(0)
+}
+
+// bucketsOf returns the buckets of epoch, of which the amounts are raw tokens under raw weighting
+func (s *Scenario) bucketsOf(epoch *epochVotes) []Bucket {
+	if s.Weighting != weightingRaw {
+		return epoch.buckets
+	}
+	buckets := make([]Bucket, len(epoch.buckets))
+	for i, bucket := range epoch.buckets {
+		buckets[i] = Bucket{owner: bucket.owner, amount: bucket.tokens, tokens: bucket.tokens}
+	}
+	return buckets
+}
+
+func simulate(
+	configPath string,
+	endpoint string,
+	bp string,
+	startEpoch uint64,
+	toEpoch uint64,
+	withFoundationBonus bool,
+	scenarios []*Scenario,
+	output string,
+) error {
+	if startEpoch == 0 || toEpoch < startEpoch {
+		return errors.Errorf("invalid epoch number from %d and to %d", startEpoch, toEpoch)
+	}
+	delegateName, err := decodeDelegateName(bp)
+	if err != nil {
+		return errors.Errorf("failed to parse bp name %s", bp)
+	}
+	committee, err := newCommittee(configPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create committee")
+	}
+	var epochs []*epochVotes
+	for epochNum := startEpoch; epochNum <= toEpoch; epochNum++ {
+		fmt.Printf("processing epoch %d\n", epochNum)
+		height, err := gravityChainHeight(endpoint, epochNum)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get gravity chain height for epoch %d", epochNum)
+		}
+		rewardAddress, _, buckets, err := readEthereum(height, delegateName, committee)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch data from ethereum for epoch %d", epochNum)
+		}
+		if len(rewardAddress) == 0 {
+			fmt.Println("no reward address specified")
+			continue
+		}
+		reward, err := getReward(endpoint, epochNum, rewardAddress, withFoundationBonus)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch reward for epoch %d", epochNum)
+		}
+		epochs = append(epochs, &epochVotes{reward: reward, buckets: buckets})
+	}
+	results := make([]*ScenarioResult, len(scenarios))
+	for i, scenario := range scenarios {
+		results[i] = scenario.run(epochs)
+		printScenario(scenario, results[i], results[0])
+	}
+	return writeSimulation(output, scenarios, results)
+}
+
+func printScenario(scenario *Scenario, result *ScenarioResult, baseline *ScenarioResult) {
+	var paid []*big.Int
+	total := big.NewInt(0)
+	for _, payout := range result.Payouts {
+		if payout.Sign() > 0 {
+			paid = append(paid, payout)
+			total.Add(total, payout)
+		}
+	}
+	sort.Slice(paid, func(i, j int) bool { return paid[i].Cmp(paid[j]) < 0 })
+	fmt.Printf("\nScenario %s:\n", scenario.Name)
+	fmt.Printf("\ttotal paid: %s IOTX to %d voters\n", util.FormatIOTX(total), len(paid))
+	fmt.Printf("\twithheld by caps and thresholds: %s IOTX\n", util.FormatIOTX(result.Withheld))
+	fmt.Printf("\tvoters below threshold: %d\n", result.BelowThreshold)
+	if len(paid) != 0 {
+		fmt.Printf(
+			"\tpayout min %s, median %s, p90 %s, max %s IOTX\n",
+			util.FormatIOTX(paid[0]),
+			util.FormatIOTX(paid[len(paid)/2]),
+			util.FormatIOTX(paid[len(paid)*9/10]),
+			util.FormatIOTX(paid[len(paid)-1]),
+		)
+	}
+	if result == baseline {
+		return
+	}
+	var gained, lost int
+	for voter, payout := range result.Payouts {
+		switch payout.Cmp(baseline.payoutOf(voter)) {
+		case 1:
+			gained++
+		case -1:
+			lost++
+		}
+	}
+	fmt.Printf("\tagainst current policy: %d voters gain, %d voters lose\n", gained, lost)
+}
+
+// writeSimulation writes the payout to each voter under all scenarios in Rau, and the change of each scenario
+// against the first one
+func writeSimulation(filename string, scenarios []*Scenario, results []*ScenarioResult) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer, err := newTableWriter(file, formatCSV, simulationColumns(scenarios), nil)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	var voters []string
+	for _, result := range results {
+		for voter := range result.Payouts {
+			if !seen[voter] {
+				seen[voter] = true
+				voters = append(voters, voter)
+			}
+		}
+	}
+	sort.Strings(voters)
+	for _, voter := range voters {
+		values := []interface{}{voter}
+		for _, result := range results {
+			values = append(values, result.payoutOf(voter))
+		}
+		for _, result := range results[1:] {
+			values = append(values, new(big.Int).Sub(result.payoutOf(voter), results[0].payoutOf(voter)))
+		}
+		if err := writer.Write(values...); err != nil {
+			return errors.Wrap(err, "error writing simulation")
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("\npayouts of %d voters under all scenarios have been written to %s\n", len(voters), filename)
+	return nil
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeScenarios writes the scenarios yaml into a temp file, and returns the file and the function to remove it
+func writeScenarios(t *testing.T, content string) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "simulate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "scenarios.yaml")
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename, func() { os.RemoveAll(dir) }
+}
+
+// simulatedEpochs returns the epochs of the delegate at heights 100 and 200 with 230 IOTX reward each
+func simulatedEpochs(t *testing.T, delegate string) []*epochVotes {
+	t.Helper()
+	committee := newFakeCommittee(t)
+	var epochs []*epochVotes
+	for _, height := range []uint64{100, 200} {
+		_, _, buckets, err := readEthereum(height, delegateName(t, delegate), committee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		epochs = append(epochs, &epochVotes{reward: iotx(230), buckets: buckets})
+	}
+	return epochs
+}
+
+func TestReadScenarios(t *testing.T) {
+	filename, remove := writeScenarios(t, `
+scenarios:
+- name: current
+  percentage: 90
+- weighting: Raw
+  percentage: 85
+  maxPayout: "300"
+  minPayout: "30.5"
+  tiers:
+  - minVotes: "2000"
+    percentage: 95
+  - minVotes: "100000"
+    percentage: 100
+`)
+	defer remove()
+	scenarios, err := readScenarios(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scenarios) != 2 || scenarios[0].Name != "current" || scenarios[1].Name != "scenario1" {
+		t.Fatalf("unexpected scenarios %+v", scenarios)
+	}
+	tiered := scenarios[1]
+	if tiered.Weighting != weightingRaw || tiered.maxPayout.Cmp(iotx(300)) != 0 {
+		t.Errorf("unexpected scenario %+v", tiered)
+	}
+	if tiered.minPayout.Cmp(new(big.Int).Add(iotx(30), new(big.Int).Div(iotx(1), big.NewInt(2)))) != 0 {
+		t.Errorf("expecting min payout 30.5 IOTX, but got %d", tiered.minPayout)
+	}
+	for _, test := range []struct {
+		votes      *big.Int
+		percentage uint
+	}{
+		{iotx(100000), 100},
+		{iotx(2000), 95},
+		{iotx(1999), 85},
+	} {
+		if pct := tiered.percentageOf(test.votes); pct != test.percentage {
+			t.Errorf("expecting %d%% of %d votes, but got %d%%", test.percentage, test.votes, pct)
+		}
+	}
+}
+
+func TestReadScenariosError(t *testing.T) {
+	for _, test := range []struct {
+		reason  string
+		content string
+	}{
+		{"no scenario", "scenarios: []\n"},
+		{"reserved name", "scenarios:\n- name: voter\n  percentage: 90\n"},
+		{"duplicate names", "scenarios:\n- name: a\n  percentage: 90\n- name: a\n  percentage: 80\n"},
+		{"duplicate default name", "scenarios:\n- percentage: 90\n- name: scenario0\n  percentage: 80\n"},
+		{"name of delta column", "scenarios:\n- name: a\n  percentage: 90\n- name: b\n  percentage: 80\n- name: bDelta\n  percentage: 70\n"},
+		{"invalid weighting", "scenarios:\n- name: a\n  percentage: 90\n  weighting: quadratic\n"},
+		{"no percentage", "scenarios:\n- name: a\n"},
+		{"invalid max payout", "scenarios:\n- name: a\n  percentage: 90\n  maxPayout: -1\n"},
+		{"unknown field", "scenarios:\n- name: a\n  percent: 90\n"},
+	} {
+		filename, remove := writeScenarios(t, test.content)
+		if _, err := readScenarios(filename); err == nil {
+			t.Errorf("expecting error of %s", test.reason)
+		}
+		remove()
+	}
+}
+
+func TestScenarioRun(t *testing.T) {
+	for _, test := range []struct {
+		reason         string
+		scenario       *Scenario
+		payouts        map[string]*big.Int
+		belowThreshold int
+		withheld       *big.Int
+	}{
+		{
+			// the same as export of 90% of 230 IOTX in epochs 1 and 2, as in TestExport
+			"current policy",
+			&Scenario{Name: "current", Percentage: 90},
+			map[string]*big.Int{voterA: iotx(69), voterB: iotx(318), voterD: iotx(27)},
+			0,
+			big.NewInt(0),
+		},
+		{
+			// B has 2000 IOTX of votes in both epochs
+			"tiers",
+			&Scenario{Name: "tiered", Percentage: 60, Tiers: []Tier{{MinVotes: "2000", Percentage: 90}}},
+			map[string]*big.Int{voterA: iotx(46), voterB: iotx(318), voterD: iotx(18)},
+			0,
+			big.NewInt(0),
+		},
+		{
+			"caps and thresholds",
+			&Scenario{Name: "capped", Percentage: 90, MaxPayout: "300", MinPayout: "30"},
+			map[string]*big.Int{voterA: iotx(69), voterB: iotx(300), voterD: big.NewInt(0)},
+			1,
+			iotx(18 + 27),
+		},
+	} {
+		if err := test.scenario.validate(); err != nil {
+			t.Fatal(err)
+		}
+		result := test.scenario.run(simulatedEpochs(t, "iotexlab"))
+		if len(result.Payouts) != len(test.payouts) {
+			t.Errorf("expecting %d payouts of %s, but got %v", len(test.payouts), test.reason, result.Payouts)
+		}
+		for voter, payout := range test.payouts {
+			if result.payoutOf(voter).Cmp(payout) != 0 {
+				t.Errorf("expecting payout %d to %s of %s, but got %d", payout, voter, test.reason, result.payoutOf(voter))
+			}
+		}
+		if result.BelowThreshold != test.belowThreshold || result.Withheld.Cmp(test.withheld) != 0 {
+			t.Errorf(
+				"expecting %d voters below threshold and %d withheld of %s, but got %d and %d",
+				test.belowThreshold,
+				test.withheld,
+				test.reason,
+				result.BelowThreshold,
+				result.Withheld,
+			)
+		}
+	}
+}
+
+func TestScenarioRunRaw(t *testing.T) {
+	weighted := &Scenario{Name: "weighted", Percentage: 100}
+	raw := &Scenario{Name: "raw", Percentage: 100, Weighting: weightingRaw}
+	for _, scenario := range []*Scenario{weighted, raw} {
+		if err := scenario.validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	epochs := simulatedEpochs(t, "robotbp")
+	// B has 500 of 600 raw tokens, and less share of weighted votes for the decaying bucket of C
+	expected := new(big.Int).Div(new(big.Int).Mul(iotx(230), big.NewInt(500)), big.NewInt(600))
+	expected.Mul(expected, big.NewInt(2))
+	if payout := raw.run(epochs).payoutOf(voterB); payout.Cmp(expected) != 0 {
+		t.Errorf("expecting raw payout %d to %s, but got %d", expected, voterB, payout)
+	}
+	if payout := weighted.run(epochs).payoutOf(voterB); payout.Cmp(expected) >= 0 {
+		t.Errorf("expecting weighted payout to %s less than %d, but got %d", voterB, expected, payout)
+	}
+}
+
+func TestWriteSimulation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simulate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	scenarios := []*Scenario{
+		{Name: "current", Percentage: 90},
+		{Name: "generous", Percentage: 100},
+	}
+	var results []*ScenarioResult
+	for _, scenario := range scenarios {
+		if err := scenario.validate(); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, scenario.run(simulatedEpochs(t, "iotexlab")))
+	}
+	filename := filepath.Join(dir, "simulation.csv")
+	if err := writeSimulation(filename, scenarios, results); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || strings.Join(rows[0], ",") != "voter,current,generous,generousDelta" {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+	// voters are sorted, and B gets 10% more of 230 IOTX for 2/3 and 20/23 of votes
+	expected := []string{voterB, iotx(318).String(), "353333333333333333333", "35333333333333333333"}
+	if strings.Join(rows[2], ",") != strings.Join(expected, ",") {
+		t.Errorf("expecting row %v, but got %v", expected, rows[2])
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	snapshotDir  string
+	offline      bool
+	snapshotFile string
+)
+
+// SnapshotCmd archives the election result at a height or epoch
+var SnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Archive the votes of all delegates at a height or epoch to a snapshot file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return snapshot(configPath, endpoint, epoch, height, snapshotFile)
+	},
+}
+
+func init() {
+	DumpCmd.AddCommand(SnapshotCmd)
+	SnapshotCmd.Flags().StringVarP(&snapshotFile, "output", "o", "", "snapshot file, HEIGHT.snapshot.gz in snapshot directory if not specified")
+}
+
+// addSnapshotFlags adds the flags of snapshot archive
+func addSnapshotFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&snapshotDir, "snapshot-dir", "", "directory of snapshots to read results from and archive fetched results to")
+	flags.BoolVar(&offline, "offline", false, "read results from snapshots only, without access to gravity chain")
+}
+
+// newCommittee creates the fetcher of election results. If a snapshot directory is given, results are read
+// from the snapshots, and fetched from gravity chain and archived if missing unless offline is set.
+func newCommittee(configPath string) (util.ResultFetcher, error) {
+	if snapshotDir == "" {
+		if offline {
+			return nil, errors.New("--offline requires --snapshot-dir")
+		}
+		return newLiveCommittee(configPath)
+	}
+	if offline {
+		return util.NewSnapshotCommittee(snapshotDir, nil)
+	}
+	committee, err := newLiveCommittee(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return util.NewSnapshotCommittee(snapshotDir, committee)
+}
+
+func snapshot(configPath string, endpoint string, epoch uint64, height uint64, filename string) error {
+	if offline {
+		return errors.New("snapshot could not be taken offline")
+	}
+	committee, err := newLiveCommittee(configPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create committee")
+	}
+	if height, err = resolveHeight(endpoint, epoch, height); err != nil {
+		return err
+	}
+	result, err := committee.FetchResultByHeight(height)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch result at height %d", height)
+	}
+	if filename == "" {
+		filename = filepath.Join(snapshotDir, util.SnapshotFileName(height))
+	}
+	if err := util.WriteSnapshot(filename, height, result); err != nil {
+		return err
+	}
+	fmt.Printf("Snapshot of height %d with %d delegates has been written to %s\n", height, len(result.Delegates()), filename)
+	return nil
+}
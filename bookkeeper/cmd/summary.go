@@ -0,0 +1,117 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"math/big"
+	"os"
+
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// summaryColumns are the columns of delegate summary
+var summaryColumns = []string{
+	"rank",
+	"name",
+	"operatorAddress",
+	"rewardAddress",
+	"votes",
+	"tokens",
+	"voters",
+	"selfStaking",
+	"share",
+}
+
+// DelegateSummary is the aggregated votes of a delegate
+type DelegateSummary struct {
+	Rank            int
+	Name            string
+	OperatorAddress string
+	RewardAddress   string
+	Votes           *big.Int
+	Tokens          *big.Int
+	Voters          int
+	SelfStaking     *big.Int
+	// Share is the percentage of the delegate's votes in total votes
+	Share string
+
+	name []byte
+}
+
+// SummaryCmd prints the leaderboard of delegates
+var SummaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Summarize votes of delegates by rank",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		writer, err := newTableWriter(os.Stdout, outputFormat, summaryColumns, columns)
+		if err != nil {
+			return err
+		}
+		result, err := fetchResult(configPath, endpoint, epoch, height)
+		if err != nil {
+			return err
+		}
+		for _, summary := range summarizeDelegates(result) {
+			if err := writer.Write(
+				summary.Rank,
+				summary.Name,
+				summary.OperatorAddress,
+				summary.RewardAddress,
+				summary.Votes,
+				summary.Tokens,
+				summary.Voters,
+				summary.SelfStaking,
+				summary.Share,
+			); err != nil {
+				return errors.Wrap(err, "error writing summary")
+			}
+		}
+		return writer.Close()
+	},
+}
+
+func init() {
+	DumpCmd.AddCommand(SummaryCmd)
+}
+
+// summarizeDelegates aggregates the votes of each delegate, in the order of rank
+func summarizeDelegates(result *types.ElectionResult) []*DelegateSummary {
+	totalVotes := result.TotalVotes()
+	var summaries []*DelegateSummary
+	for i, delegate := range result.Delegates() {
+		tokens := big.NewInt(0)
+		voters := make(map[string]bool)
+		for _, vote := range result.VotesByDelegate(delegate.Name()) {
+			tokens.Add(tokens, vote.Amount())
+			voters[string(vote.Voter())] = true
+		}
+		share := "0"
+		if totalVotes.Sign() > 0 {
+			share = new(big.Rat).SetFrac(
+				new(big.Int).Mul(delegate.Score(), big.NewInt(100)),
+				totalVotes,
+			).FloatString(4)
+		}
+		summaries = append(summaries, &DelegateSummary{
+			Rank:            i + 1,
+			Name:            delegateNameString(delegate.Name()),
+			OperatorAddress: string(delegate.OperatorAddress()),
+			RewardAddress:   string(delegate.RewardAddress()),
+			Votes:           delegate.Score(),
+			Tokens:          tokens,
+			Voters:          len(voters),
+			SelfStaking:     delegate.SelfStakingTokens(),
+			Share:           share,
+			name:            delegate.Name(),
+		})
+	}
+	return summaries
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-core/protogen/iotexapi"
+	"github.com/iotexproject/iotex-core/protogen/iotextypes"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// receiptStatusSuccess is the status of a successful receipt
+const receiptStatusSuccess = 1
+
+// PayoutReceipt is the result of a multisend call read from its receipt
+type PayoutReceipt struct {
+	ActionHash  string
+	Token       common.Address
+	TotalAmount *big.Int
+	Tips        *big.Int
+	Payload     string
+	Recipients  []common.Address
+	Amounts     []*big.Int
+}
+
+// VerifyPayoutCmd verifies the transfers of multisend actions against csv
+var VerifyPayoutCmd = &cobra.Command{
+	Use:   "verify-payout csv action-hash...",
+	Short: "Verify the transfers in receipts of multisend actions against csv",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		mode, err := util.ParseRoundingMode(rounding)
+		if err != nil {
+			return err
+		}
+		return verifyPayout(args[0], args[1:], inputUnit, mode, endpoint)
+	},
+}
+
+func init() {
+	addEndpointFlags(VerifyPayoutCmd.Flags())
+	VerifyPayoutCmd.Flags().StringVar(&inputUnit, "input-unit", "Rau", "unit of amount in csv")
+	VerifyPayoutCmd.Flags().StringVar(&rounding, "rounding", "none", "rounding mode of amounts in csv finer than 1 Rau")
+}
+
+func verifyPayout(csvFile string, actionHashes []string, unit string, mode util.RoundingMode, endpoint string) error {
+	if err := checkActionHashes(actionHashes); err != nil {
+		return err
+	}
+	records, err := readRecords(csvFile, unit, mode)
+	if err != nil {
+		return err
+	}
+	conn, err := dial(endpoint)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	cli := iotexapi.NewAPIServiceClient(conn)
+	var (
+		recipients []common.Address
+		amounts    []*big.Int
+		failures   int
+	)
+	totalTips := big.NewInt(0)
+	for _, actionHash := range actionHashes {
+		receipt, err := fetchPayoutReceipt(cli, actionHash)
+		if err != nil {
+			fmt.Printf("failed: %s %v\n", actionHash, err)
+			failures++
+			continue
+		}
+		fmt.Printf(
+			"action %s: %d transfers, total %d Rau, tips %d Rau, payload %s\n",
+			receipt.ActionHash,
+			len(receipt.Recipients),
+			receipt.TotalAmount,
+			receipt.Tips,
+			receipt.Payload,
+		)
+		recipients = append(recipients, receipt.Recipients...)
+		amounts = append(amounts, receipt.Amounts...)
+		totalTips.Add(totalTips, receipt.Tips)
+	}
+	addrs, expectedAmounts, total := splitRecords(records)
+	diffs := compareAmounts(addrs, expectedAmounts, recipients, amounts, "transferred")
+	fmt.Printf("Expected Total: %s IOTX or %d Rau\n", util.FormatIOTX(total), total)
+	fmt.Printf("Tips Charged: %s IOTX or %d Rau\n", util.FormatIOTX(totalTips), totalTips)
+	if failures != 0 || diffs != 0 {
+		return errors.Errorf("%d actions failed and %d entries differ from %s", failures, diffs, csvFile)
+	}
+	fmt.Printf("all transfers match %s\n", csvFile)
+	return nil
+}
+
+// checkActionHashes rejects an action hash given more than once, which would count its transfers twice. Hashes
+// are compared without 0x prefix and case.
+func checkActionHashes(actionHashes []string) error {
+	seen := make(map[string]bool, len(actionHashes))
+	var duplicates []string
+	for _, actionHash := range actionHashes {
+		key := strings.ToLower(strings.TrimPrefix(actionHash, "0x"))
+		if seen[key] {
+			duplicates = append(duplicates, actionHash)
+			continue
+		}
+		seen[key] = true
+	}
+	if len(duplicates) != 0 {
+		return errors.Errorf("duplicate action hashes %s", strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+// fetchPayoutReceipt reads the receipt of a multisend action, and decodes the Transfer and Receipt events emitted
+// by the multisend contract the action executes. Events of other contracts, e.g., the Transfer events of the token
+// in sendToken, are ignored.
+func fetchPayoutReceipt(cli iotexapi.APIServiceClient, actionHash string) (*PayoutReceipt, error) {
+	multisendABI, err := abi.JSON(strings.NewReader(MultisendABI))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid multisend abi")
+	}
+	actionHash = strings.TrimPrefix(actionHash, "0x")
+	contractAddr, err := executionContract(cli, actionHash)
+	if err != nil {
+		return nil, err
+	}
+	response, err := cli.GetReceiptByAction(
+		context.Background(),
+		&iotexapi.GetReceiptByActionRequest{ActionHash: actionHash},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get receipt")
+	}
+	if response.ReceiptInfo == nil || response.ReceiptInfo.Receipt == nil {
+		return nil, errors.New("receipt is empty")
+	}
+	receipt := response.ReceiptInfo.Receipt
+	if receipt.Status != receiptStatusSuccess {
+		return nil, errors.Errorf("action is not executed successfully, status %d", receipt.Status)
+	}
+	transferEvent := multisendABI.Events["Transfer"]
+	receiptEvent := multisendABI.Events["Receipt"]
+	payoutReceipt := &PayoutReceipt{ActionHash: actionHash}
+	var receipts int
+	for _, log := range receipt.Logs {
+		if log.ContractAddress != contractAddr || len(log.Topics) == 0 {
+			continue
+		}
+		switch {
+		case bytes.Equal(log.Topics[0], transferEvent.Id().Bytes()):
+			to, value, err := decodeTransfer(&transferEvent, log)
+			if err != nil {
+				return nil, err
+			}
+			payoutReceipt.Recipients = append(payoutReceipt.Recipients, to)
+			payoutReceipt.Amounts = append(payoutReceipt.Amounts, value)
+		case bytes.Equal(log.Topics[0], receiptEvent.Id().Bytes()):
+			values, err := receiptEvent.Inputs.NonIndexed().UnpackValues(log.Data)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to unpack Receipt event")
+			}
+			var ok1, ok2, ok3, ok4 bool
+			payoutReceipt.Token, ok1 = values[0].(common.Address)
+			payoutReceipt.TotalAmount, ok2 = values[1].(*big.Int)
+			payoutReceipt.Tips, ok3 = values[2].(*big.Int)
+			payoutReceipt.Payload, ok4 = values[3].(string)
+			if !ok1 || !ok2 || !ok3 || !ok4 {
+				return nil, errors.Errorf("invalid Receipt event %v", values)
+			}
+			receipts++
+		}
+	}
+	if receipts != 1 {
+		return nil, errors.Errorf("expecting one Receipt event, but got %d", receipts)
+	}
+	transferred := big.NewInt(0)
+	for _, amount := range payoutReceipt.Amounts {
+		transferred.Add(transferred, amount)
+	}
+	if transferred.Cmp(payoutReceipt.TotalAmount) != 0 {
+		return nil, errors.Errorf(
+			"transferred %d Rau does not match total amount %d Rau in Receipt event",
+			transferred,
+			payoutReceipt.TotalAmount,
+		)
+	}
+	return payoutReceipt, nil
+}
+
+// executionContract returns the address of the contract the action executes
+func executionContract(cli iotexapi.APIServiceClient, actionHash string) (string, error) {
+	response, err := cli.GetActions(context.Background(), &iotexapi.GetActionsRequest{
+		Lookup: &iotexapi.GetActionsRequest_ByHash{
+			ByHash: &iotexapi.GetActionByHashRequest{ActionHash: actionHash},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get action")
+	}
+	if len(response.ActionInfo) == 0 || response.ActionInfo[0].Action == nil {
+		return "", errors.New("action not found")
+	}
+	execution := response.ActionInfo[0].Action.GetCore().GetExecution()
+	if execution == nil {
+		return "", errors.New("action is not an execution")
+	}
+	return execution.Contract, nil
+}
+
+func decodeTransfer(event *abi.Event, log *iotextypes.Log) (common.Address, *big.Int, error) {
+	// topics are event id, indexed from and indexed to
+	if len(log.Topics) != 3 {
+		return common.Address{}, nil, errors.Errorf("expecting 3 topics in Transfer event, but got %d", len(log.Topics))
+	}
+	values, err := event.Inputs.NonIndexed().UnpackValues(log.Data)
+	if err != nil {
+		return common.Address{}, nil, errors.Wrap(err, "failed to unpack Transfer event")
+	}
+	value, ok := values[0].(*big.Int)
+	if !ok {
+		return common.Address{}, nil, errors.Errorf("invalid value %v in Transfer event", values[0])
+	}
+	return common.BytesToAddress(log.Topics[2]), value, nil
+}
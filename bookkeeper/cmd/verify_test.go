@@ -0,0 +1,184 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/protogen/iotexapi"
+	"github.com/iotexproject/iotex-core/protogen/iotextypes"
+	"github.com/iotexproject/iotex-tools/util"
+	"google.golang.org/grpc"
+)
+
+const multisendAddress = "0x2222222222222222222222222222222222222222"
+
+// receiptClient serves a single action and its receipt
+type receiptClient struct {
+	iotexapi.APIServiceClient
+	action  *iotextypes.Action
+	receipt *iotextypes.Receipt
+}
+
+func (c *receiptClient) GetActions(
+	ctx context.Context,
+	in *iotexapi.GetActionsRequest,
+	opts ...grpc.CallOption,
+) (*iotexapi.GetActionsResponse, error) {
+	return &iotexapi.GetActionsResponse{ActionInfo: []*iotexapi.ActionInfo{{Action: c.action}}}, nil
+}
+
+func (c *receiptClient) GetReceiptByAction(
+	ctx context.Context,
+	in *iotexapi.GetReceiptByActionRequest,
+	opts ...grpc.CallOption,
+) (*iotexapi.GetReceiptByActionResponse, error) {
+	if c.receipt == nil {
+		return &iotexapi.GetReceiptByActionResponse{}, nil
+	}
+	return &iotexapi.GetReceiptByActionResponse{ReceiptInfo: &iotexapi.ReceiptInfo{Receipt: c.receipt}}, nil
+}
+
+func ioAddress(t *testing.T, hexAddr string) string {
+	t.Helper()
+	addr, err := address.FromBytes(common.HexToAddress(hexAddr).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr.String()
+}
+
+// payoutLogs returns the logs of a sendToken payout of payoutRecords, including the Transfer events emitted by the
+// token itself
+func payoutLogs(t *testing.T) []*iotextypes.Log {
+	t.Helper()
+	multisendABI, err := abi.JSON(strings.NewReader(MultisendABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transferEvent := multisendABI.Events["Transfer"]
+	receiptEvent := multisendABI.Events["Receipt"]
+	multisend := ioAddress(t, multisendAddress)
+	token := ioAddress(t, tokenAddress)
+	var logs []*iotextypes.Log
+	for _, record := range payoutRecords() {
+		data, err := transferEvent.Inputs.NonIndexed().Pack(record.Amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		topics := [][]byte{
+			transferEvent.Id().Bytes(),
+			common.HexToAddress(multisendAddress).Hash().Bytes(),
+			record.Address.Hash().Bytes(),
+		}
+		logs = append(
+			logs,
+			&iotextypes.Log{ContractAddress: token, Topics: topics, Data: data},
+			&iotextypes.Log{ContractAddress: multisend, Topics: topics, Data: data},
+		)
+	}
+	data, err := receiptEvent.Inputs.NonIndexed().Pack(common.HexToAddress(tokenAddress), iotx(3), big.NewInt(1), "epoch 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(logs, &iotextypes.Log{
+		ContractAddress: multisend,
+		Topics:          [][]byte{receiptEvent.Id().Bytes()},
+		Data:            data,
+	})
+}
+
+func executionAction(contract string) *iotextypes.Action {
+	return &iotextypes.Action{Core: &iotextypes.ActionCore{
+		Action: &iotextypes.ActionCore_Execution{Execution: &iotextypes.Execution{Contract: contract}},
+	}}
+}
+
+func TestFetchPayoutReceipt(t *testing.T) {
+	cli := &receiptClient{
+		action:  executionAction(ioAddress(t, multisendAddress)),
+		receipt: &iotextypes.Receipt{Status: receiptStatusSuccess, Logs: payoutLogs(t)},
+	}
+	receipt, err := fetchPayoutReceipt(cli, "0xabcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.ActionHash != "abcd" || receipt.Token != common.HexToAddress(tokenAddress) || receipt.Payload != "epoch 1" {
+		t.Errorf("unexpected receipt %+v", receipt)
+	}
+	if receipt.TotalAmount.Cmp(iotx(3)) != 0 || receipt.Tips.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("expecting total %d and tips 1, but got %d and %d", iotx(3), receipt.TotalAmount, receipt.Tips)
+	}
+	// transfers of the token are not counted
+	records := payoutRecords()
+	if len(receipt.Recipients) != len(records) {
+		t.Fatalf("expecting %d transfers, but got %d", len(records), len(receipt.Recipients))
+	}
+	for i, record := range records {
+		if receipt.Recipients[i] != record.Address || receipt.Amounts[i].Cmp(record.Amount) != 0 {
+			t.Errorf("expecting %s %d, but got %s %d", record.Address.Hex(), record.Amount, receipt.Recipients[i].Hex(), receipt.Amounts[i])
+		}
+	}
+}
+
+func TestFetchPayoutReceiptError(t *testing.T) {
+	multisend := ioAddress(t, multisendAddress)
+	for _, test := range []struct {
+		reason string
+		cli    *receiptClient
+	}{
+		{"empty receipt", &receiptClient{action: executionAction(multisend)}},
+		{"not an execution", &receiptClient{
+			action:  &iotextypes.Action{Core: &iotextypes.ActionCore{}},
+			receipt: &iotextypes.Receipt{Status: receiptStatusSuccess, Logs: payoutLogs(t)},
+		}},
+		{"failed execution", &receiptClient{
+			action:  executionAction(multisend),
+			receipt: &iotextypes.Receipt{Status: 0, Logs: payoutLogs(t)},
+		}},
+		{"execution of another contract", &receiptClient{
+			action:  executionAction(ioAddress(t, tokenAddress)),
+			receipt: &iotextypes.Receipt{Status: receiptStatusSuccess, Logs: payoutLogs(t)},
+		}},
+		{"missing transfer", &receiptClient{
+			action:  executionAction(multisend),
+			receipt: &iotextypes.Receipt{Status: receiptStatusSuccess, Logs: payoutLogs(t)[2:]},
+		}},
+	} {
+		if _, err := fetchPayoutReceipt(test.cli, "abcd"); err == nil {
+			t.Errorf("expecting error of %s", test.reason)
+		}
+	}
+}
+
+func TestCheckActionHashes(t *testing.T) {
+	for _, test := range []struct {
+		hashes []string
+		valid  bool
+	}{
+		{[]string{"abcd"}, true},
+		{[]string{"abcd", "abce"}, true},
+		{[]string{"abcd", "abcd"}, false},
+		{[]string{"abcd", "0xabcd"}, false},
+		{[]string{"ABCD", "abce", "abcd"}, false},
+	} {
+		if err := checkActionHashes(test.hashes); (err == nil) != test.valid {
+			t.Errorf("expecting %v to be valid %t, but got %v", test.hashes, test.valid, err)
+		}
+	}
+	// duplicates are rejected before the csv is read or any receipt is fetched
+	err := verifyPayout("missing.csv", []string{"abcd", "0xABCD"}, "Rau", util.RoundNone, "")
+	if err == nil || !strings.Contains(err.Error(), "duplicate action hashes 0xABCD") {
+		t.Errorf("expecting error of duplicate action hash, but got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// rowBucket is the type of a row of bucket
+	rowBucket = "bucket"
+	// rowTotal is the type of a row of the total of a delegate, or of all delegates if delegate is empty
+	rowTotal = "total"
+)
+
+// voterColumns are the columns of voter portfolio. Start time, duration, decay and expiry are empty in the
+// rows of totals.
+var voterColumns = []string{
+	"delegate",
+	"type",
+	"tokens",
+	"votes",
+	"startTime",
+	"duration",
+	"decay",
+	"expiry",
+}
+
+// VoterCmd lists the buckets of a voter across all delegates
+var VoterCmd = &cobra.Command{
+	Use:   "voter address",
+	Short: "List the buckets of a voter across all delegates",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		voter, err := util.ParseAddress(args[0])
+		if err != nil {
+			return err
+		}
+		writer, err := newTableWriter(os.Stdout, outputFormat, voterColumns, columns)
+		if err != nil {
+			return err
+		}
+		result, err := fetchResult(configPath, endpoint, epoch, height)
+		if err != nil {
+			return err
+		}
+		return voterPortfolio(result, voter.Bytes(), writer)
+	},
+}
+
+func init() {
+	DumpCmd.AddCommand(VoterCmd)
+}
+
+func voterPortfolio(result *types.ElectionResult, voter []byte, writer *tableWriter) error {
+	filter := &voteFilter{voters: [][]byte{voter}, minAmount: big.NewInt(0)}
+	totalTokens := big.NewInt(0)
+	totalVotes := big.NewInt(0)
+	for _, delegate := range result.Delegates() {
+		name := delegateNameString(delegate.Name())
+		tokens := big.NewInt(0)
+		votes := big.NewInt(0)
+		var buckets int
+		for _, vote := range result.VotesByDelegate(delegate.Name()) {
+			if !filter.matchVote(vote) {
+				continue
+			}
+			if err := writer.Write(
+				name,
+				rowBucket,
+				vote.Amount(),
+				vote.WeightedAmount(),
+				vote.StartTime().UTC().Format(time.RFC3339),
+				int64(vote.Duration().Seconds()),
+				vote.Decay(),
+				voteExpiry(vote),
+			); err != nil {
+				return errors.Wrap(err, "error writing bucket")
+			}
+			tokens.Add(tokens, vote.Amount())
+			votes.Add(votes, vote.WeightedAmount())
+			buckets++
+		}
+		if buckets == 0 {
+			continue
+		}
+		if err := writer.Write(name, rowTotal, tokens, votes, "", "", "", ""); err != nil {
+			return errors.Wrap(err, "error writing total")
+		}
+		totalTokens.Add(totalTokens, tokens)
+		totalVotes.Add(totalVotes, votes)
+	}
+	if err := writer.Write("", rowTotal, totalTokens, totalVotes, "", "", "", ""); err != nil {
+		return errors.Wrap(err, "error writing total")
+	}
+	return writer.Close()
+}
+
+// voteExpiry returns the time a vote expires. A decaying vote expires at the end of its duration, while a
+// non-decaying vote keeps its full duration until decay is turned on, so it has no expiry.
+func voteExpiry(vote *types.Vote) string {
+	if !vote.Decay() {
+		return ""
+	}
+	return expiry(vote).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var tolerance string
+
+// weightColumns are the columns of mismatched weights
+var weightColumns = []string{
+	"delegate",
+	"voter",
+	"startTime",
+	"duration",
+	"decay",
+	"tokens",
+	"reported",
+	"recomputed",
+	"delta",
+}
+
+// VerifyWeightsCmd recomputes the weighted votes of all buckets and reports the mismatched ones
+var VerifyWeightsCmd = &cobra.Command{
+	Use:   "verify-weights",
+	Short: "Recompute the weighted votes of all buckets and report the ones differing from the result",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		maxDelta, ok := new(big.Int).SetString(tolerance, 10)
+		if !ok || maxDelta.Sign() < 0 {
+			return errors.Errorf("invalid tolerance %s", tolerance)
+		}
+		writer, err := newTableWriter(os.Stdout, outputFormat, weightColumns, columns)
+		if err != nil {
+			return err
+		}
+		var result *types.ElectionResult
+		if snapshotFile != "" {
+			_, result, err = util.ReadSnapshot(snapshotFile)
+		} else {
+			result, err = fetchResult(configPath, endpoint, epoch, height)
+		}
+		if err != nil {
+			return err
+		}
+		return verifyWeights(result, maxDelta, writer)
+	},
+}
+
+func init() {
+	DumpCmd.AddCommand(VerifyWeightsCmd)
+	VerifyWeightsCmd.Flags().StringVar(&snapshotFile, "snapshot", "", "snapshot file to verify instead of fetching the result")
+	VerifyWeightsCmd.Flags().StringVar(&tolerance, "tolerance", "0", "max difference in Rau allowed between reported and recomputed votes")
+}
+
+// verifyWeights recomputes the weighted votes of all buckets at the mint time of result
+func verifyWeights(result *types.ElectionResult, tolerance *big.Int, writer *tableWriter) error {
+	now := result.MintTime()
+	var buckets, mismatches int
+	for _, delegate := range result.Delegates() {
+		for _, vote := range result.VotesByDelegate(delegate.Name()) {
+			buckets++
+			recomputed := util.WeightedVotes(vote.Amount(), vote.StartTime(), vote.Duration(), vote.Decay(), now)
+			delta := new(big.Int).Sub(recomputed, vote.WeightedAmount())
+			if new(big.Int).Abs(delta).Cmp(tolerance) <= 0 {
+				continue
+			}
+			mismatches++
+			if err := writer.Write(
+				delegateNameString(delegate.Name()),
+				hex.EncodeToString(vote.Voter()),
+				vote.StartTime().UTC().Format(time.RFC3339),
+				int64(vote.Duration().Seconds()),
+				vote.Decay(),
+				vote.Amount(),
+				vote.WeightedAmount(),
+				recomputed,
+				delta,
+			); err != nil {
+				return errors.Wrap(err, "error writing mismatch")
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if mismatches != 0 {
+		return errors.Errorf("%d of %d buckets have weighted votes differing from recomputed ones", mismatches, buckets)
+	}
+	return nil
+}
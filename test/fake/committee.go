@@ -0,0 +1,272 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package fake provides in-process stand-ins of the gravity chain and the iotex chain built from fixtures, such
+// that the code depending on them could be tested deterministically.
+package fake
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iotexproject/iotex-election/committee"
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+)
+
+// CommitteeFixture is the election results of a committee at gravity chain heights
+type CommitteeFixture struct {
+	Results []*ResultFixture `json:"results"`
+}
+
+// ResultFixture is the delegates and votes at a gravity chain height
+type ResultFixture struct {
+	Height    uint64             `json:"height"`
+	MintTime  time.Time          `json:"mintTime"`
+	Delegates []*DelegateFixture `json:"delegates"`
+	Votes     []*VoteFixture     `json:"votes"`
+}
+
+// DelegateFixture is a candidate registered in the register contract
+type DelegateFixture struct {
+	// Name is the readable name, or 24 hex digits of the raw name, as export takes
+	Name              string `json:"name"`
+	Address           string `json:"address"`
+	OperatorAddress   string `json:"operatorAddress"`
+	RewardAddress     string `json:"rewardAddress"`
+	SelfStakingWeight uint64 `json:"selfStakingWeight"`
+}
+
+// VoteFixture is a bucket in the staking contract
+type VoteFixture struct {
+	Voter     string    `json:"voter"`
+	Candidate string    `json:"candidate"`
+	Amount    string    `json:"amount"`
+	StartTime time.Time `json:"startTime"`
+	// Duration is a duration string, e.g., 720h
+	Duration string `json:"duration"`
+	Decay    bool   `json:"decay"`
+	// WeightedAmount overrides the weighted amount computed with the weighting formula, if not empty
+	WeightedAmount string `json:"weightedAmount,omitempty"`
+}
+
+// Committee is a committee serving the election results built from fixtures
+type Committee struct {
+	results map[uint64]*types.ElectionResult
+	errs    map[uint64]error
+	fetches map[uint64]int
+	mutex   sync.Mutex
+}
+
+var _ committee.Committee = (*Committee)(nil)
+
+// NewCommitteeFromFile creates a committee from a json fixture file
+func NewCommitteeFromFile(filename string) (*Committee, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read fixture %s", filename)
+	}
+	var fixture CommitteeFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal fixture %s", filename)
+	}
+	return NewCommittee(&fixture)
+}
+
+// NewCommittee creates a committee from fixture
+func NewCommittee(fixture *CommitteeFixture) (*Committee, error) {
+	c := &Committee{
+		results: make(map[uint64]*types.ElectionResult),
+		errs:    make(map[uint64]error),
+		fetches: make(map[uint64]int),
+	}
+	for _, rf := range fixture.Results {
+		result, err := rf.Result()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid fixture of height %d", rf.Height)
+		}
+		c.results[rf.Height] = result
+	}
+	return c, nil
+}
+
+// Result calculates the election result of the fixture, with the weighting formula of iotex-election
+func (rf *ResultFixture) Result() (*types.ElectionResult, error) {
+	var candidates []*types.Candidate
+	for _, df := range rf.Delegates {
+		name, err := DelegateName(df.Name)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := util.ParseAddress(df.Address)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, types.NewCandidate(
+			name,
+			addr.Bytes(),
+			[]byte(df.OperatorAddress),
+			[]byte(df.RewardAddress),
+			df.SelfStakingWeight,
+		))
+	}
+	overrides := make(map[string]*big.Int)
+	var votes []*types.Vote
+	for i, vf := range rf.Votes {
+		vote, err := vf.vote()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid vote %d", i)
+		}
+		if vf.WeightedAmount != "" {
+			weighted, ok := new(big.Int).SetString(vf.WeightedAmount, 10)
+			if !ok {
+				return nil, errors.Errorf("invalid weighted amount %s of vote %d", vf.WeightedAmount, i)
+			}
+			overrides[voteKey(vote)] = weighted
+		}
+		votes = append(votes, vote)
+	}
+	calcScore := func(vote *types.Vote, now time.Time) *big.Int {
+		if weighted, ok := overrides[voteKey(vote)]; ok {
+			return weighted
+		}
+		return util.WeightedVotes(vote.Amount(), vote.StartTime(), vote.Duration(), vote.Decay(), now)
+	}
+	calculator := types.NewResultCalculator(
+		rf.MintTime,
+		func(*types.Vote) bool { return false },
+		calcScore,
+		func(*types.Candidate) bool { return false },
+	)
+	if err := calculator.AddCandidates(candidates); err != nil {
+		return nil, err
+	}
+	if err := calculator.AddVotes(votes); err != nil {
+		return nil, err
+	}
+	return calculator.Calculate()
+}
+
+func (vf *VoteFixture) vote() (*types.Vote, error) {
+	voter, err := util.ParseAddress(vf.Voter)
+	if err != nil {
+		return nil, err
+	}
+	candidate, err := DelegateName(vf.Candidate)
+	if err != nil {
+		return nil, err
+	}
+	amount, ok := new(big.Int).SetString(vf.Amount, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid amount %s", vf.Amount)
+	}
+	duration, err := time.ParseDuration(vf.Duration)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewVote(vf.StartTime, duration, amount, big.NewInt(0), voter.Bytes(), candidate, vf.Decay)
+}
+
+// voteKey identifies a vote, which is kept when the vote is cloned
+func voteKey(vote *types.Vote) string {
+	return string(vote.Voter()) + string(vote.Candidate()) + vote.StartTime().String() + vote.Amount().String()
+}
+
+// DelegateName returns the raw name of a delegate, which is 24 hex digits, or a readable name left padded with
+// zeros to 12 bytes
+func DelegateName(name string) ([]byte, error) {
+	if len(name) == 24 {
+		if raw, err := hex.DecodeString(name); err == nil {
+			return raw, nil
+		}
+	}
+	if len(name) > 12 {
+		return nil, errors.Errorf("delegate name %s is longer than 12 bytes", name)
+	}
+	raw := make([]byte, 12-len(name))
+	return append(raw, name...), nil
+}
+
+// SetError makes the fetch at height fail with err, or succeed again if err is nil
+func (c *Committee) SetError(height uint64, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err == nil {
+		delete(c.errs, height)
+		return
+	}
+	c.errs[height] = err
+}
+
+// Fetches returns the number of fetches at height
+func (c *Committee) Fetches(height uint64) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.fetches[height]
+}
+
+// Start does nothing
+func (c *Committee) Start(context.Context) error { return nil }
+
+// Stop does nothing
+func (c *Committee) Stop(context.Context) error { return nil }
+
+// ResultByHeight returns the result at height
+func (c *Committee) ResultByHeight(height uint64) (*types.ElectionResult, error) {
+	return c.FetchResultByHeight(height)
+}
+
+// FetchResultByHeight returns the result at height, or an error if there is no fixture of the height
+func (c *Committee) FetchResultByHeight(height uint64) (*types.ElectionResult, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.fetches[height]++
+	if err, ok := c.errs[height]; ok {
+		return nil, err
+	}
+	result, ok := c.results[height]
+	if !ok {
+		return nil, errors.Errorf("no fixture of height %d", height)
+	}
+	return result, nil
+}
+
+// HeightByTime returns the highest height of which the mint time is not after timestamp
+func (c *Committee) HeightByTime(timestamp time.Time) (uint64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var heights []uint64
+	for height, result := range c.results {
+		if !result.MintTime().After(timestamp) {
+			heights = append(heights, height)
+		}
+	}
+	if len(heights) == 0 {
+		return 0, errors.Errorf("no fixture before %s", timestamp)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights[len(heights)-1], nil
+}
+
+// LatestHeight returns the highest height of fixtures
+func (c *Committee) LatestHeight() uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var latest uint64
+	for height := range c.results {
+		if height > latest {
+			latest = height
+		}
+	}
+	return latest
+}
@@ -0,0 +1,440 @@
+// Copyright (c) 2019 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package fake
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding/rewardingpb"
+	"github.com/iotexproject/iotex-core/protogen/iotexapi"
+	"github.com/iotexproject/iotex-core/protogen/iotextypes"
+	"github.com/iotexproject/iotex-tools/util"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rewardTypes are the reward types in fixtures
+var rewardTypes = map[string]rewardingpb.RewardLog_RewardType{
+	"block":      rewardingpb.RewardLog_BLOCK_REWARD,
+	"epoch":      rewardingpb.RewardLog_EPOCH_REWARD,
+	"foundation": rewardingpb.RewardLog_FOUNDATION_BONUS,
+}
+
+// ChainFixture is the epochs and blocks of an iotex chain
+type ChainFixture struct {
+	// NumDelegates and NumSubEpochs are the epoch geometry, which are 24 and 15 if not given
+	NumDelegates uint64            `json:"numDelegates"`
+	NumSubEpochs uint64            `json:"numSubEpochs"`
+	Epochs       []*EpochFixture   `json:"epochs"`
+	Blocks       []*BlockFixture   `json:"blocks"`
+	Accounts     []*AccountFixture `json:"accounts"`
+	Reads        []*ReadFixture    `json:"reads"`
+}
+
+// EpochFixture is the meta of an epoch
+type EpochFixture struct {
+	Num                     uint64 `json:"num"`
+	GravityChainStartHeight uint64 `json:"gravityChainStartHeight"`
+}
+
+// BlockFixture is a block and its actions
+type BlockFixture struct {
+	Height  uint64           `json:"height"`
+	Hash    string           `json:"hash"`
+	Actions []*ActionFixture `json:"actions"`
+}
+
+// ActionFixture is an action, which is a grant reward action with reward logs in its receipt, or a transfer
+type ActionFixture struct {
+	Hash        string           `json:"hash"`
+	GrantReward bool             `json:"grantReward"`
+	Rewards     []*RewardFixture `json:"rewards"`
+}
+
+// AccountFixture is the balance of an account, of which the address is either an io1 or a 0x address
+type AccountFixture struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+}
+
+// ReadFixture is the canned result of a read only call of contract, which is either an io1 or a 0x address
+type ReadFixture struct {
+	Contract string `json:"contract"`
+	// Data is the hex encoded call data, and Result is the hex encoded return data
+	Data   string `json:"data"`
+	Result string `json:"result"`
+}
+
+// RewardFixture is a reward log in the receipt of a grant reward action
+type RewardFixture struct {
+	// Type is one of block, epoch and foundation
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+}
+
+// Server is an iotex api service serving the epochs, blocks, actions, receipts, accounts and contract reads built
+// from fixture. The methods other than GetEpochMeta, GetBlockMetas, GetActions, GetReceiptByAction, GetAccount and
+// ReadContract return codes.Unimplemented.
+type Server struct {
+	blocksPerEpoch uint64
+	epochs         map[uint64]*EpochFixture
+	blocks         map[uint64]*BlockFixture
+	blockHashes    map[string]*BlockFixture
+	receipts       map[string]*iotextypes.Receipt
+	actionBlocks   map[string]string
+	balances       map[common.Address]string
+	reads          map[readKey]string
+	calls          map[string]int
+	mutex          sync.Mutex
+	server         *grpc.Server
+}
+
+// NewServerFromFile creates a server from a json fixture file
+func NewServerFromFile(filename string) (*Server, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read fixture %s", filename)
+	}
+	var fixture ChainFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal fixture %s", filename)
+	}
+	return NewServer(&fixture)
+}
+
+// NewServer creates a server from fixture
+func NewServer(fixture *ChainFixture) (*Server, error) {
+	numDelegates, numSubEpochs := fixture.NumDelegates, fixture.NumSubEpochs
+	if numDelegates == 0 {
+		numDelegates = 24
+	}
+	if numSubEpochs == 0 {
+		numSubEpochs = 15
+	}
+	s := &Server{
+		blocksPerEpoch: numDelegates * numSubEpochs,
+		epochs:         make(map[uint64]*EpochFixture),
+		blocks:         make(map[uint64]*BlockFixture),
+		blockHashes:    make(map[string]*BlockFixture),
+		receipts:       make(map[string]*iotextypes.Receipt),
+		actionBlocks:   make(map[string]string),
+		balances:       make(map[common.Address]string),
+		reads:          make(map[readKey]string),
+		calls:          make(map[string]int),
+	}
+	for _, ef := range fixture.Epochs {
+		s.epochs[ef.Num] = ef
+	}
+	for _, bf := range fixture.Blocks {
+		if _, ok := s.blocks[bf.Height]; ok {
+			return nil, errors.Errorf("duplicate block of height %d", bf.Height)
+		}
+		s.blocks[bf.Height] = bf
+		s.blockHashes[bf.Hash] = bf
+		for i, af := range bf.Actions {
+			receipt, err := af.receipt(bf.Height)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid action %d of block %d", i, bf.Height)
+			}
+			s.receipts[af.Hash] = receipt
+			s.actionBlocks[af.Hash] = bf.Hash
+		}
+	}
+	for _, af := range fixture.Accounts {
+		addr, err := util.ParseAddress(af.Address)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid account")
+		}
+		s.balances[addr] = af.Balance
+	}
+	for _, rf := range fixture.Reads {
+		key, err := newReadKey(rf.Contract, rf.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid read")
+		}
+		s.reads[key] = rf.Result
+	}
+	return s, nil
+}
+
+// readKey is the contract and the call data of a read only call
+type readKey struct {
+	contract common.Address
+	data     string
+}
+
+func newReadKey(contract string, data string) (readKey, error) {
+	addr, err := util.ParseAddress(contract)
+	if err != nil {
+		return readKey{}, err
+	}
+	return readKey{contract: addr, data: strings.ToLower(strings.TrimPrefix(data, "0x"))}, nil
+}
+
+func (af *ActionFixture) receipt(height uint64) (*iotextypes.Receipt, error) {
+	actHash, err := hex.DecodeString(af.Hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid action hash %s", af.Hash)
+	}
+	receipt := &iotextypes.Receipt{Status: 1, ActHash: actHash}
+	for i, rf := range af.Rewards {
+		rewardType, ok := rewardTypes[rf.Type]
+		if !ok {
+			return nil, errors.Errorf("invalid reward type %s", rf.Type)
+		}
+		data, err := proto.Marshal(&rewardingpb.RewardLog{
+			Type:   rewardType,
+			Addr:   rf.Address,
+			Amount: rf.Amount,
+		})
+		if err != nil {
+			return nil, err
+		}
+		receipt.Logs = append(receipt.Logs, &iotextypes.Log{
+			Data:      data,
+			BlkHeight: height,
+			ActHash:   actHash,
+			Index:     uint32(i),
+		})
+	}
+	return receipt, nil
+}
+
+func (af *ActionFixture) action() *iotextypes.Action {
+	core := &iotextypes.ActionCore{Version: 1}
+	if af.GrantReward {
+		core.Action = &iotextypes.ActionCore_GrantReward{
+			GrantReward: &iotextypes.GrantReward{},
+		}
+	} else {
+		core.Action = &iotextypes.ActionCore_Transfer{
+			Transfer: &iotextypes.Transfer{Amount: "0"},
+		}
+	}
+	return &iotextypes.Action{Core: core}
+}
+
+var _ iotexapi.APIServiceServer = (*Server)(nil)
+
+// Start serves on a random local port, and returns the address to dial without TLS
+func (s *Server) Start() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to listen")
+	}
+	s.server = grpc.NewServer()
+	iotexapi.RegisterAPIServiceServer(s.server, s)
+	go s.server.Serve(listener)
+	return listener.Addr().String(), nil
+}
+
+// Stop stops serving
+func (s *Server) Stop() {
+	if s.server != nil {
+		s.server.Stop()
+	}
+}
+
+// Calls returns the number of calls of method
+func (s *Server) Calls(method string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.calls[method]
+}
+
+func (s *Server) called(method string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.calls[method]++
+}
+
+// GetEpochMeta returns the meta of an epoch
+func (s *Server) GetEpochMeta(
+	ctx context.Context,
+	in *iotexapi.GetEpochMetaRequest,
+) (*iotexapi.GetEpochMetaResponse, error) {
+	s.called("GetEpochMeta")
+	ef, ok := s.epochs[in.EpochNumber]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no fixture of epoch %d", in.EpochNumber)
+	}
+	return &iotexapi.GetEpochMetaResponse{
+		EpochData: &iotextypes.EpochData{
+			Num:                     ef.Num,
+			Height:                  (ef.Num-1)*s.blocksPerEpoch + 1,
+			GravityChainStartHeight: ef.GravityChainStartHeight,
+		},
+		TotalBlocks: s.blocksPerEpoch,
+	}, nil
+}
+
+// GetBlockMetas returns the metas of blocks by index
+func (s *Server) GetBlockMetas(
+	ctx context.Context,
+	in *iotexapi.GetBlockMetasRequest,
+) (*iotexapi.GetBlockMetasResponse, error) {
+	s.called("GetBlockMetas")
+	byIndex := in.GetByIndex()
+	if byIndex == nil {
+		return nil, status.Error(codes.Unimplemented, "only blocks by index are served")
+	}
+	response := &iotexapi.GetBlockMetasResponse{}
+	for height := byIndex.Start; height < byIndex.Start+byIndex.Count; height++ {
+		bf, ok := s.blocks[height]
+		if !ok {
+			continue
+		}
+		response.BlkMetas = append(response.BlkMetas, &iotextypes.BlockMeta{
+			Hash:       bf.Hash,
+			Height:     bf.Height,
+			NumActions: int64(len(bf.Actions)),
+		})
+	}
+	if len(response.BlkMetas) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no fixture of blocks from height %d", byIndex.Start)
+	}
+	response.Total = uint64(len(response.BlkMetas))
+	return response, nil
+}
+
+// GetActions returns the actions in a block
+func (s *Server) GetActions(
+	ctx context.Context,
+	in *iotexapi.GetActionsRequest,
+) (*iotexapi.GetActionsResponse, error) {
+	s.called("GetActions")
+	byBlk := in.GetByBlk()
+	if byBlk == nil {
+		return nil, status.Error(codes.Unimplemented, "only actions by block are served")
+	}
+	bf, ok := s.blockHashes[byBlk.BlkHash]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no fixture of block %s", byBlk.BlkHash)
+	}
+	response := &iotexapi.GetActionsResponse{Total: uint64(len(bf.Actions))}
+	for i := byBlk.Start; i < uint64(len(bf.Actions)) && i < byBlk.Start+byBlk.Count; i++ {
+		af := bf.Actions[i]
+		response.ActionInfo = append(response.ActionInfo, &iotexapi.ActionInfo{
+			Action:  af.action(),
+			ActHash: af.Hash,
+			BlkHash: bf.Hash,
+		})
+	}
+	return response, nil
+}
+
+// GetReceiptByAction returns the receipt of an action
+func (s *Server) GetReceiptByAction(
+	ctx context.Context,
+	in *iotexapi.GetReceiptByActionRequest,
+) (*iotexapi.GetReceiptByActionResponse, error) {
+	s.called("GetReceiptByAction")
+	receipt, ok := s.receipts[in.ActionHash]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no fixture of action %s", in.ActionHash)
+	}
+	return &iotexapi.GetReceiptByActionResponse{
+		ReceiptInfo: &iotexapi.ReceiptInfo{Receipt: receipt, BlkHash: s.actionBlocks[in.ActionHash]},
+	}, nil
+}
+
+// GetAccount returns the balance of an account
+func (s *Server) GetAccount(
+	ctx context.Context,
+	in *iotexapi.GetAccountRequest,
+) (*iotexapi.GetAccountResponse, error) {
+	s.called("GetAccount")
+	addr, err := util.ParseAddress(in.Address)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	balance, ok := s.balances[addr]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no fixture of account %s", in.Address)
+	}
+	return &iotexapi.GetAccountResponse{
+		AccountMeta: &iotextypes.AccountMeta{Address: in.Address, Balance: balance},
+	}, nil
+}
+
+// GetChainMeta is not implemented
+func (s *Server) GetChainMeta(
+	ctx context.Context,
+	in *iotexapi.GetChainMetaRequest,
+) (*iotexapi.GetChainMetaResponse, error) {
+	return nil, s.unimplemented("GetChainMeta")
+}
+
+// GetServerMeta is not implemented
+func (s *Server) GetServerMeta(
+	ctx context.Context,
+	in *iotexapi.GetServerMetaRequest,
+) (*iotexapi.GetServerMetaResponse, error) {
+	return nil, s.unimplemented("GetServerMeta")
+}
+
+// SendAction is not implemented
+func (s *Server) SendAction(
+	ctx context.Context,
+	in *iotexapi.SendActionRequest,
+) (*iotexapi.SendActionResponse, error) {
+	return nil, s.unimplemented("SendAction")
+}
+
+// ReadContract returns the canned result of the execution in action, by its contract and data
+func (s *Server) ReadContract(
+	ctx context.Context,
+	in *iotexapi.ReadContractRequest,
+) (*iotexapi.ReadContractResponse, error) {
+	s.called("ReadContract")
+	execution := in.GetAction().GetCore().GetExecution()
+	if execution == nil {
+		return nil, status.Error(codes.InvalidArgument, "action is not an execution")
+	}
+	key, err := newReadKey(execution.Contract, hex.EncodeToString(execution.Data))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	result, ok := s.reads[key]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no fixture of reading %x from %s", execution.Data, execution.Contract)
+	}
+	return &iotexapi.ReadContractResponse{Data: result}, nil
+}
+
+// SuggestGasPrice is not implemented
+func (s *Server) SuggestGasPrice(
+	ctx context.Context,
+	in *iotexapi.SuggestGasPriceRequest,
+) (*iotexapi.SuggestGasPriceResponse, error) {
+	return nil, s.unimplemented("SuggestGasPrice")
+}
+
+// EstimateGasForAction is not implemented
+func (s *Server) EstimateGasForAction(
+	ctx context.Context,
+	in *iotexapi.EstimateGasForActionRequest,
+) (*iotexapi.EstimateGasForActionResponse, error) {
+	return nil, s.unimplemented("EstimateGasForAction")
+}
+
+// unimplemented counts the call of a method not served from fixture, and returns its error
+func (s *Server) unimplemented(method string) error {
+	s.called(method)
+	return status.Errorf(codes.Unimplemented, "%s is not served by fake server", method)
+}
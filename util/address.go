@@ -0,0 +1,53 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+)
+
+// ParseAddress parses an io1 or 0x address strictly. Bech32 checksums of io1 addresses are always
+// verified, while EIP-55 checksums of 0x addresses are verified if the address is in mixed case.
+func ParseAddress(s string) (common.Address, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "io") {
+		addr, err := address.FromString(s)
+		if err != nil {
+			return common.Address{}, errors.Wrapf(err, "invalid io address %s", s)
+		}
+		return common.BytesToAddress(addr.Bytes()), nil
+	}
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return common.Address{}, errors.Errorf("address %s is neither an io nor a 0x address", s)
+	}
+	hexStr := s[2:]
+	if len(hexStr) != 2*common.AddressLength {
+		return common.Address{}, errors.Errorf(
+			"invalid address length of %s, expecting %d hex digits but got %d",
+			s,
+			2*common.AddressLength,
+			len(hexStr),
+		)
+	}
+	if _, err := hex.DecodeString(hexStr); err != nil {
+		return common.Address{}, errors.Wrapf(err, "invalid hex address %s", s)
+	}
+	addr := common.HexToAddress(hexStr)
+	if hexStr != strings.ToLower(hexStr) && hexStr != strings.ToUpper(hexStr) && addr.Hex() != "0x"+hexStr {
+		return common.Address{}, errors.Errorf("invalid checksum of address %s, expecting %s", s, addr.Hex())
+	}
+	return addr, nil
+}
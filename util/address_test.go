@@ -0,0 +1,75 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-address/address"
+)
+
+// checksummed is an address in EIP-55 mixed case
+const checksummed = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+func TestParseAddress(t *testing.T) {
+	expected := common.HexToAddress(checksummed)
+	ioAddr, err := address.FromBytes(expected.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// replace the last character of the io address with another bech32 character, which breaks its checksum
+	encoded := ioAddr.String()
+	last := "q"
+	if strings.HasSuffix(encoded, last) {
+		last = "p"
+	}
+	badChecksum := encoded[:len(encoded)-1] + last
+	for _, test := range []struct {
+		input string
+		valid bool
+	}{
+		{encoded, true},
+		{" " + encoded + " ", true},
+		{badChecksum, false},
+		{"io1", false},
+		{checksummed, true},
+		{strings.ToLower(checksummed), true},
+		{"0x" + strings.ToUpper(checksummed[2:]), true},
+		{"0X" + strings.ToLower(checksummed[2:]), true},
+		// the case of one letter is flipped
+		{"0x5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false},
+		{checksummed[:len(checksummed)-1], false},
+		{checksummed + "d", false},
+		{"0x", false},
+		{strings.ToLower(checksummed)[2:], false},
+		{"", false},
+		{"iotex", false},
+		{"0xzzzeb6053f3e94c9b9a09f33669435e7ef1beaed", false},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1be-ed", false},
+	} {
+		addr, err := ParseAddress(test.input)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("expecting error of %q, but got %s", test.input, addr.Hex())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("expecting %q to be valid, but got %v", test.input, err)
+			continue
+		}
+		if addr != expected {
+			t.Errorf("expecting %s of %q, but got %s", expected.Hex(), test.input, addr.Hex())
+		}
+	}
+}
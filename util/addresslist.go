@@ -0,0 +1,51 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// ReadAddressList reads a file of io or 0x addresses, one per line. Blank lines and anything after
+// a "#" are ignored.
+func ReadAddressList(filename string) (map[common.Address]bool, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open address list %s", filename)
+	}
+	defer f.Close()
+	addrs := make(map[common.Address]bool)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		addr, err := ParseAddress(text)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid address in %s line %d", filename, line)
+		}
+		addrs[addr] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read address list %s", filename)
+	}
+	return addrs, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IOTXDecimals is the number of decimals of IOTX, i.e., 1 IOTX = 10^18 Rau
+const IOTXDecimals = 18
+
+// decimalPattern matches a non-negative decimal without sign, exponent or fraction notation
+var decimalPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// RoundingMode defines how an amount finer than the smallest unit is rounded
+type RoundingMode string
+
+const (
+	// RoundNone rejects amounts which cannot be represented exactly
+	RoundNone RoundingMode = "none"
+	// RoundDown rounds towards zero
+	RoundDown RoundingMode = "down"
+	// RoundUp rounds away from zero
+	RoundUp RoundingMode = "up"
+	// RoundHalfUp rounds to the nearest, and ties away from zero
+	RoundHalfUp RoundingMode = "half-up"
+	// RoundHalfEven rounds to the nearest, and ties to the even neighbour
+	RoundHalfEven RoundingMode = "half-even"
+)
+
+// ParseRoundingMode parses a rounding mode by name
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	mode := RoundingMode(strings.ToLower(s))
+	switch mode {
+	case RoundNone, RoundDown, RoundUp, RoundHalfUp, RoundHalfEven:
+		return mode, nil
+	default:
+		return "", errors.Errorf("invalid rounding mode %s", s)
+	}
+}
+
+// ParseAmount parses a non-negative decimal string exactly and scales it by 10^decimals. The exact scaled value
+// is returned along with the integer amount, so that callers could report how much rounding changed it.
+func ParseAmount(s string, decimals int, mode RoundingMode) (*big.Int, *big.Rat, error) {
+	s = strings.TrimSpace(s)
+	if !decimalPattern.MatchString(s) {
+		return nil, nil, errors.Errorf("failed to parse amount %s", s)
+	}
+	exact, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, nil, errors.Errorf("failed to parse amount %s", s)
+	}
+	exact.Mul(exact, new(big.Rat).SetInt(pow10(decimals)))
+	if exact.IsInt() {
+		return new(big.Int).Set(exact.Num()), exact, nil
+	}
+	if mode == RoundNone {
+		return nil, nil, errors.Errorf("amount %s has more than %d fractional digits", s, decimals)
+	}
+	amount, err := round(exact, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return amount, exact, nil
+}
+
+// FormatAmount formats an integer amount as a fixed-point decimal with the given decimals
+func FormatAmount(amount *big.Int, decimals int) string {
+	abs := new(big.Int).Abs(amount)
+	digits := abs.String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	str := digits
+	if decimals > 0 {
+		str = digits[:len(digits)-decimals] + "." + digits[len(digits)-decimals:]
+	}
+	if amount.Sign() < 0 {
+		return "-" + str
+	}
+	return str
+}
+
+// FormatIOTX formats an amount in Rau as IOTX
+func FormatIOTX(amountInRau *big.Int) string {
+	return FormatAmount(amountInRau, IOTXDecimals)
+}
+
+func round(exact *big.Rat, mode RoundingMode) (*big.Int, error) {
+	num := new(big.Int).Abs(exact.Num())
+	denom := exact.Denom()
+	quo, rem := new(big.Int).QuoRem(num, denom, new(big.Int))
+	switch mode {
+	case RoundDown:
+	case RoundUp:
+		if rem.Sign() != 0 {
+			quo.Add(quo, big.NewInt(1))
+		}
+	case RoundHalfUp, RoundHalfEven:
+		switch new(big.Int).Lsh(rem, 1).Cmp(denom) {
+		case 1:
+			quo.Add(quo, big.NewInt(1))
+		case 0:
+			if mode == RoundHalfUp || quo.Bit(0) == 1 {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+	default:
+		return nil, errors.Errorf("invalid rounding mode %s", mode)
+	}
+	if exact.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		decimals int
+		mode     RoundingMode
+		amount   string
+		exact    string
+	}{
+		{"0", IOTXDecimals, RoundNone, "0", "0"},
+		{"12", 0, RoundNone, "12", "12"},
+		{" 12 ", 0, RoundNone, "12", "12"},
+		{"1", IOTXDecimals, RoundNone, "1000000000000000000", "1000000000000000000"},
+		{"1234.123456789012345678", IOTXDecimals, RoundNone, "1234123456789012345678", "1234123456789012345678"},
+		{"0.000000000000000001", IOTXDecimals, RoundNone, "1", "1"},
+		{"007.50", 2, RoundNone, "750", "750"},
+		// 19 fractional digits are rounded under the mode
+		{"0.0000000000000000015", IOTXDecimals, RoundDown, "1", "3/2"},
+		{"0.0000000000000000015", IOTXDecimals, RoundUp, "2", "3/2"},
+		{"0.0000000000000000015", IOTXDecimals, RoundHalfUp, "2", "3/2"},
+		{"0.0000000000000000015", IOTXDecimals, RoundHalfEven, "2", "3/2"},
+		{"0.0000000000000000025", IOTXDecimals, RoundHalfEven, "2", "5/2"},
+		{"0.0000000000000000025", IOTXDecimals, RoundHalfUp, "3", "5/2"},
+		{"0.0000000000000000014", IOTXDecimals, RoundHalfUp, "1", "7/5"},
+		{"0.0000000000000000016", IOTXDecimals, RoundHalfEven, "2", "8/5"},
+		{"1.5", 0, RoundDown, "1", "3/2"},
+		{"1.01", 0, RoundUp, "2", "101/100"},
+	} {
+		amount, exact, err := ParseAmount(test.input, test.decimals, test.mode)
+		if err != nil {
+			t.Errorf("failed to parse %q with %d decimals in %s mode: %v", test.input, test.decimals, test.mode, err)
+			continue
+		}
+		if amount.String() != test.amount || exact.RatString() != test.exact {
+			t.Errorf(
+				"expecting %q with %d decimals in %s mode to be %s (exactly %s), but got %d (exactly %s)",
+				test.input,
+				test.decimals,
+				test.mode,
+				test.amount,
+				test.exact,
+				amount,
+				exact.RatString(),
+			)
+		}
+	}
+}
+
+func TestParseAmountError(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		decimals int
+		mode     RoundingMode
+	}{
+		// more fractional digits than decimals without rounding
+		{"0.0000000000000000001", IOTXDecimals, RoundNone},
+		{"1.5", 0, RoundNone},
+		// negative values
+		{"-1", IOTXDecimals, RoundNone},
+		{"-0.5", 0, RoundDown},
+		{"+1", IOTXDecimals, RoundNone},
+		// exponents
+		{"1e5", IOTXDecimals, RoundNone},
+		{"1E-19", IOTXDecimals, RoundDown},
+		// fractions
+		{"1/3", IOTXDecimals, RoundHalfUp},
+		// other notations
+		{"0x10", 0, RoundNone},
+		{"1,000", 0, RoundNone},
+		{".5", IOTXDecimals, RoundNone},
+		{"5.", IOTXDecimals, RoundNone},
+		{"", IOTXDecimals, RoundNone},
+		{"ten", IOTXDecimals, RoundNone},
+		{"1.5", 0, RoundingMode("nearest")},
+	} {
+		if amount, _, err := ParseAmount(test.input, test.decimals, test.mode); err == nil {
+			t.Errorf("expecting error of %q with %d decimals in %s mode, but got %d", test.input, test.decimals, test.mode, amount)
+		}
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	mode, err := ParseRoundingMode("Half-Even")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode != RoundHalfEven {
+		t.Errorf("expecting %s, but got %s", RoundHalfEven, mode)
+	}
+	if _, err := ParseRoundingMode("nearest"); err == nil {
+		t.Error("expecting error of unknown rounding mode")
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	for _, test := range []struct {
+		amount   string
+		decimals int
+		output   string
+	}{
+		{"0", IOTXDecimals, "0.000000000000000000"},
+		{"1", IOTXDecimals, "0.000000000000000001"},
+		{"1000000000000000000", IOTXDecimals, "1.000000000000000000"},
+		{"1234123456789012345678", IOTXDecimals, "1234.123456789012345678"},
+		{"-1500000000000000000", IOTXDecimals, "-1.500000000000000000"},
+		{"-1", 2, "-0.01"},
+		{"750", 2, "7.50"},
+		{"42", 0, "42"},
+	} {
+		amount, ok := new(big.Int).SetString(test.amount, 10)
+		if !ok {
+			t.Fatalf("invalid amount %s", test.amount)
+		}
+		if output := FormatAmount(amount, test.decimals); output != test.output {
+			t.Errorf("expecting %s with %d decimals to be formatted as %s, but got %s", test.amount, test.decimals, test.output, output)
+		}
+	}
+	if output := FormatIOTX(big.NewInt(5)); output != "0.000000000000000005" {
+		t.Errorf("unexpected IOTX %s", output)
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "1", "999999999999999999", "1234123456789012345678"} {
+		amount, _ := new(big.Int).SetString(s, 10)
+		parsed, _, err := ParseAmount(FormatIOTX(amount), IOTXDecimals, RoundNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.Cmp(amount) != 0 {
+			t.Errorf("expecting %s after round trip, but got %d", s, parsed)
+		}
+	}
+}
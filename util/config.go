@@ -0,0 +1,202 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/iotexproject/iotex-election/committee"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// EnvPrefix is the prefix of environment variables overriding config
+const EnvPrefix = "IOTEX_TOOLS_"
+
+// gravityAPIPlaceholder is the placeholder of api key in the gravity chain api of the sample config
+const gravityAPIPlaceholder = "YOUR_INFURA_PROJECT_ID"
+
+// configEnv maps the environment variables to the setters of config fields
+var configEnv = []struct {
+	name string
+	set  func(*committee.Config, string) error
+}{
+	{"GRAVITY_API", func(c *committee.Config, v string) error {
+		c.GravityChainAPIs = splitList(v)
+		return nil
+	}},
+	{"GRAVITY_START_HEIGHT", func(c *committee.Config, v string) (err error) {
+		c.GravityChainStartHeight, err = strconv.ParseUint(v, 10, 64)
+		return
+	}},
+	{"GRAVITY_HEIGHT_INTERVAL", func(c *committee.Config, v string) (err error) {
+		c.GravityChainHeightInterval, err = strconv.ParseUint(v, 10, 64)
+		return
+	}},
+	{"REGISTER_CONTRACT", func(c *committee.Config, v string) error {
+		c.RegisterContractAddress = v
+		return nil
+	}},
+	{"STAKING_CONTRACT", func(c *committee.Config, v string) error {
+		c.StakingContractAddress = v
+		return nil
+	}},
+	{"VOTE_THRESHOLD", func(c *committee.Config, v string) error {
+		c.VoteThreshold = v
+		return nil
+	}},
+	{"SCORE_THRESHOLD", func(c *committee.Config, v string) error {
+		c.ScoreThreshold = v
+		return nil
+	}},
+	{"SELF_STAKING_THRESHOLD", func(c *committee.Config, v string) error {
+		c.SelfStakingThreshold = v
+		return nil
+	}},
+	{"NUM_OF_RETRIES", func(c *committee.Config, v string) error {
+		n, err := strconv.ParseUint(v, 10, 8)
+		c.NumOfRetries = uint8(n)
+		return err
+	}},
+	{"PAGINATION_SIZE", func(c *committee.Config, v string) error {
+		n, err := strconv.ParseUint(v, 10, 8)
+		c.PaginationSize = uint8(n)
+		return err
+	}},
+	{"CACHE_SIZE", func(c *committee.Config, v string) error {
+		n, err := strconv.ParseUint(v, 10, 32)
+		c.CacheSize = uint32(n)
+		return err
+	}},
+}
+
+// LoadCommitteeConfig reads the config file, and overrides it with environment variables. Unknown fields in the
+// file are rejected. The file is skipped if filename is empty. The config is not validated, as it may be further
+// overridden, e.g., by flags, so call ValidateCommitteeConfig before use.
+func LoadCommitteeConfig(filename string) (committee.Config, error) {
+	var config committee.Config
+	if filename != "" {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return config, errors.Wrapf(err, "failed to load config file %s", filename)
+		}
+		if err := yaml.UnmarshalStrict(data, &config); err != nil {
+			return config, errors.Wrapf(err, "failed to unmarshal config file %s", filename)
+		}
+	}
+	if err := ApplyConfigEnv(&config); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// ApplyConfigEnv overrides the config with the environment variables set, e.g., IOTEX_TOOLS_GRAVITY_API
+// as comma separated gravity chain apis
+func ApplyConfigEnv(config *committee.Config) error {
+	for _, env := range configEnv {
+		value, ok := os.LookupEnv(EnvPrefix + env.name)
+		if !ok {
+			continue
+		}
+		if err := env.set(config, strings.TrimSpace(value)); err != nil {
+			return errors.Wrapf(err, "invalid %s%s", EnvPrefix, env.name)
+		}
+	}
+	return nil
+}
+
+// ValidateCommitteeConfig checks the required fields, contract addresses and thresholds of config, and returns
+// all problems found in one error
+func ValidateCommitteeConfig(config committee.Config) error {
+	var problems []string
+	if len(config.GravityChainAPIs) == 0 {
+		problems = append(problems, "gravityChainAPIs is required")
+	}
+	for _, api := range config.GravityChainAPIs {
+		if strings.Contains(api, gravityAPIPlaceholder) {
+			problems = append(problems, fmt.Sprintf("gravity chain api %s has no api key, replace %s", api, gravityAPIPlaceholder))
+			continue
+		}
+		u, err := url.Parse(api)
+		if err != nil || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("gravity chain api %s is not a url", MaskURL(api)))
+			continue
+		}
+		switch u.Scheme {
+		case "http", "https", "ws", "wss":
+		default:
+			problems = append(problems, fmt.Sprintf("gravity chain api %s is not http(s) or ws(s)", MaskURL(api)))
+		}
+	}
+	if config.GravityChainStartHeight == 0 {
+		problems = append(problems, "gravityChainStartHeight is required")
+	}
+	if config.GravityChainHeightInterval == 0 {
+		problems = append(problems, "gravityChainHeightInterval should be positive")
+	}
+	if config.PaginationSize == 0 {
+		problems = append(problems, "paginationSize should be positive")
+	}
+	for name, addr := range map[string]string{
+		"registerContractAddress": config.RegisterContractAddress,
+		"stakingContractAddress":  config.StakingContractAddress,
+	} {
+		if addr == "" {
+			problems = append(problems, name+" is required")
+		} else if _, err := ParseAddress(addr); err != nil || !common.IsHexAddress(addr) {
+			problems = append(problems, fmt.Sprintf("%s %s is not a valid 0x address", name, addr))
+		}
+	}
+	for name, threshold := range map[string]string{
+		"voteThreshold":        config.VoteThreshold,
+		"scoreThreshold":       config.ScoreThreshold,
+		"selfStakingThreshold": config.SelfStakingThreshold,
+	} {
+		if value, ok := new(big.Int).SetString(threshold, 10); !ok || value.Sign() < 0 {
+			problems = append(problems, fmt.Sprintf("%s %q is not a non-negative integer", name, threshold))
+		}
+	}
+	if len(problems) != 0 {
+		sort.Strings(problems)
+		return errors.Errorf("invalid config:\n\t%s", strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
+
+// MaskURL hides the path and query of a url, which usually carry api keys
+func MaskURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	if (u.Path == "" || u.Path == "/") && u.RawQuery == "" {
+		return s
+	}
+	return u.Scheme + "://" + u.Host + "/***"
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
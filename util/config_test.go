@@ -0,0 +1,72 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/iotex-election/committee"
+)
+
+func TestSampleCommitteeConfig(t *testing.T) {
+	config, err := LoadCommitteeConfig("../committee.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the sample config has no api key
+	if err := ValidateCommitteeConfig(config); err == nil {
+		t.Error("expecting error of placeholder gravity chain api")
+	}
+	if err := os.Setenv(EnvPrefix+"GRAVITY_API", "https://mainnet.infura.io/v3/key1, wss://mainnet.infura.io/ws/v3/key1"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(EnvPrefix + "GRAVITY_API")
+	if config, err = LoadCommitteeConfig("../committee.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.GravityChainAPIs) != 2 || config.GravityChainAPIs[1] != "wss://mainnet.infura.io/ws/v3/key1" {
+		t.Errorf("unexpected gravity chain apis %v", config.GravityChainAPIs)
+	}
+	if err := ValidateCommitteeConfig(config); err != nil {
+		t.Errorf("expecting config with api from environment to be valid, but got %v", err)
+	}
+}
+
+func TestNewCommitteeWithConfigFile(t *testing.T) {
+	// websocket apis are not health checked, so the committee is created without network
+	override := func(config *committee.Config) {
+		config.GravityChainAPIs = []string{"wss://mainnet.infura.io/ws/v3/key1"}
+	}
+	c, err := NewCommitteeWithConfigFile("../committee.yaml", WithConfigOverride(override), WithRateLimit(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, ok := c.(*FailoverCommittee)
+	if !ok {
+		t.Fatalf("expecting a failover committee, but got %T", c)
+	}
+	if len(fc.endpoints) != 1 || fc.endpoints[0].config.GravityChainAPIs[0] != "wss://mainnet.infura.io/ws/v3/key1" {
+		t.Errorf("expecting the api of override, but got %+v", fc.Health())
+	}
+	if fc.endpoints[0].interval != 250*time.Millisecond {
+		t.Errorf("expecting interval 250ms, but got %s", fc.endpoints[0].interval)
+	}
+	// the sample config is invalid without override, and no committee is returned
+	c, err = NewCommitteeWithConfigFile("../committee.yaml")
+	if err == nil {
+		t.Error("expecting error of placeholder gravity chain api")
+	}
+	if c != nil {
+		t.Errorf("expecting nil committee, but got %v", c)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"time"
+
+	"github.com/iotexproject/iotex-election/committee"
+)
+
+// NewFailoverCommitteeOf creates a failover committee of the committees ranked in order without health check, such
+// that the tests in util_test could fail over between fake committees. Each endpoint fetches at most once per
+// interval.
+func NewFailoverCommitteeOf(urls []string, committees []committee.Committee, interval time.Duration) *FailoverCommittee {
+	fc := &FailoverCommittee{}
+	for i, c := range committees {
+		fc.endpoints = append(fc.endpoints, &gravityEndpoint{
+			health:    &EndpointHealth{URL: urls[i]},
+			committee: c,
+			interval:  interval,
+		})
+	}
+	return fc
+}
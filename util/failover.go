@@ -0,0 +1,319 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iotexproject/iotex-election/committee"
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// healthCheckTimeout is the timeout of checking the health of an endpoint
+const healthCheckTimeout = 5 * time.Second
+
+// ErrHealthCheckNotSupported is returned when checking the health of a non-http endpoint
+var ErrHealthCheckNotSupported = errors.New("health check is only supported for http(s) endpoints")
+
+// EndpointHealth is the health of a gravity chain endpoint, observed by health check and fetches
+type EndpointHealth struct {
+	URL string
+	// Latency is the moving average of the latency of successful requests
+	Latency  time.Duration
+	Requests int
+	Errors   int
+	// Checked is false if the endpoint could not be health checked, e.g., a websocket endpoint
+	Checked bool
+}
+
+// ErrorRate returns the ratio of failed requests
+func (h *EndpointHealth) ErrorRate() float64 {
+	if h.Requests == 0 {
+		return 0
+	}
+	return float64(h.Errors) / float64(h.Requests)
+}
+
+// observe records the result of a request
+func (h *EndpointHealth) observe(latency time.Duration, err error) {
+	h.Requests++
+	if err != nil {
+		h.Errors++
+		return
+	}
+	if h.Latency == 0 {
+		h.Latency = latency
+	} else {
+		h.Latency = (h.Latency*3 + latency) / 4
+	}
+}
+
+// less returns true if the endpoint is healthier than the other one, by error rate and then latency. An endpoint
+// of unknown latency ranks after the measured ones, such that a successful fetch does not demote the endpoint.
+func (h *EndpointHealth) less(other *EndpointHealth) bool {
+	if h.ErrorRate() != other.ErrorRate() {
+		return h.ErrorRate() < other.ErrorRate()
+	}
+	if h.Checked != other.Checked {
+		return h.Checked
+	}
+	if (h.Latency == 0) != (other.Latency == 0) {
+		return h.Latency != 0
+	}
+	return h.Latency < other.Latency
+}
+
+// CheckEndpoint checks the health of an http(s) gravity chain endpoint with eth_blockNumber
+func CheckEndpoint(api string, timeout time.Duration) (time.Duration, error) {
+	u, err := url.Parse(api)
+	if err != nil {
+		return 0, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return 0, ErrHealthCheckNotSupported
+	}
+	body := []byte(`{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`)
+	client := &http.Client{Timeout: timeout}
+	start := time.Now()
+	response, err := client.Post(api, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return 0, errors.Errorf("unexpected status %s", response.Status)
+	}
+	var result struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return 0, errors.Wrap(err, "invalid json-rpc response")
+	}
+	if result.Error != nil {
+		return 0, errors.New(result.Error.Message)
+	}
+	if !strings.HasPrefix(result.Result, "0x") {
+		return 0, errors.Errorf("invalid block number %s", result.Result)
+	}
+	return time.Since(start), nil
+}
+
+// gravityEndpoint is a committee fetching results from one gravity chain endpoint
+type gravityEndpoint struct {
+	health    *EndpointHealth
+	config    committee.Config
+	committee committee.Committee
+	// interval is the min interval between two fetches, no limit if zero
+	interval  time.Duration
+	lastFetch time.Time
+	mutex     sync.Mutex
+}
+
+// call calls do with the committee of the endpoint after waiting for the rate limit, and returns the latency of do
+func (e *gravityEndpoint) call(do func(committee.Committee) error) (time.Duration, error) {
+	e.mutex.Lock()
+	if e.committee == nil {
+		c, err := committee.NewCommittee(nil, e.config)
+		if err != nil {
+			e.mutex.Unlock()
+			return 0, err
+		}
+		e.committee = c
+	}
+	// reserve the next slot of the rate limit, such that concurrent fetches are spread out
+	slot := time.Now()
+	if next := e.lastFetch.Add(e.interval); next.After(slot) {
+		slot = next
+	}
+	e.lastFetch = slot
+	c := e.committee
+	e.mutex.Unlock()
+	time.Sleep(time.Until(slot))
+	start := time.Now()
+	err := do(c)
+	return time.Since(start), err
+}
+
+// FailoverCommittee fetches results from the healthiest gravity chain endpoint, and fails over to the next one
+// if it fails. Endpoints are ranked by health check at creation, and re-ranked by the error rate and latency
+// of fetches. Results are fetched on demand, so it needs not be started.
+type FailoverCommittee struct {
+	endpoints []*gravityEndpoint
+	mutex     sync.RWMutex
+}
+
+var _ committee.Committee = (*FailoverCommittee)(nil)
+
+// NewFailoverCommittee health checks the gravity chain apis of config, and creates a committee of them. Each
+// endpoint is limited to rate fetches per second, no limit if rate is zero.
+func NewFailoverCommittee(config committee.Config, rate float64) (*FailoverCommittee, error) {
+	if err := ValidateCommitteeConfig(config); err != nil {
+		return nil, err
+	}
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Duration(float64(time.Second) / rate)
+	}
+	fc := &FailoverCommittee{}
+	var wg sync.WaitGroup
+	for _, api := range config.GravityChainAPIs {
+		endpointConfig := config
+		endpointConfig.GravityChainAPIs = []string{api}
+		endpoint := &gravityEndpoint{
+			health:   &EndpointHealth{URL: api},
+			config:   endpointConfig,
+			interval: interval,
+		}
+		fc.endpoints = append(fc.endpoints, endpoint)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			latency, err := CheckEndpoint(endpoint.health.URL, healthCheckTimeout)
+			switch {
+			case err == ErrHealthCheckNotSupported:
+				return
+			case err != nil:
+				zap.L().Warn("Gravity chain endpoint is unhealthy", zap.String("endpoint", MaskURL(endpoint.health.URL)), zap.Error(err))
+			default:
+				endpoint.health.Checked = true
+			}
+			endpoint.health.observe(latency, err)
+		}()
+	}
+	wg.Wait()
+	fc.rank()
+	for i, endpoint := range fc.endpoints {
+		zap.L().Info(
+			"Ranked gravity chain endpoint",
+			zap.Int("rank", i+1),
+			zap.String("endpoint", MaskURL(endpoint.health.URL)),
+			zap.Duration("latency", endpoint.health.Latency),
+			zap.Float64("errorRate", endpoint.health.ErrorRate()),
+		)
+	}
+	return fc, nil
+}
+
+// Health returns the health of endpoints in the order of rank
+func (fc *FailoverCommittee) Health() []EndpointHealth {
+	fc.mutex.RLock()
+	defer fc.mutex.RUnlock()
+	health := make([]EndpointHealth, len(fc.endpoints))
+	for i, endpoint := range fc.endpoints {
+		health[i] = *endpoint.health
+	}
+	return health
+}
+
+// Start does nothing, as results are fetched on demand
+func (fc *FailoverCommittee) Start(context.Context) error { return nil }
+
+// Stop does nothing
+func (fc *FailoverCommittee) Stop(context.Context) error { return nil }
+
+// ResultByHeight fetches the result at height, as FetchResultByHeight does
+func (fc *FailoverCommittee) ResultByHeight(height uint64) (*types.ElectionResult, error) {
+	return fc.FetchResultByHeight(height)
+}
+
+// FetchResultByHeight fetches the result at height from the endpoints in the order of rank, until one succeeds
+func (fc *FailoverCommittee) FetchResultByHeight(height uint64) (*types.ElectionResult, error) {
+	var result *types.ElectionResult
+	if err := fc.failover(fmt.Sprintf("fetch result at height %d", height), func(c committee.Committee) (err error) {
+		result, err = c.FetchResultByHeight(height)
+		return
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// HeightByTime returns the height at timestamp from the endpoints in the order of rank, until one succeeds
+func (fc *FailoverCommittee) HeightByTime(timestamp time.Time) (uint64, error) {
+	var height uint64
+	if err := fc.failover(fmt.Sprintf("get height at %s", timestamp), func(c committee.Committee) (err error) {
+		height, err = c.HeightByTime(timestamp)
+		return
+	}); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+// LatestHeight returns the latest height known by the endpoints in the order of rank, or 0 if none knows. It does
+// not affect the ranking, as an endpoint knows no height until a result is fetched from it.
+func (fc *FailoverCommittee) LatestHeight() uint64 {
+	for _, endpoint := range fc.ranked() {
+		var height uint64
+		if _, err := endpoint.call(func(c committee.Committee) error {
+			height = c.LatestHeight()
+			return nil
+		}); err == nil && height != 0 {
+			return height
+		}
+	}
+	return 0
+}
+
+// ranked returns a copy of the endpoints in the order of rank
+func (fc *FailoverCommittee) ranked() []*gravityEndpoint {
+	fc.mutex.RLock()
+	defer fc.mutex.RUnlock()
+	endpoints := make([]*gravityEndpoint, len(fc.endpoints))
+	copy(endpoints, fc.endpoints)
+	return endpoints
+}
+
+// failover calls do with the committees of the endpoints in the order of rank until one succeeds, and re-ranks
+// the endpoints by the error and latency of each call. If all fail, the errors of all endpoints are returned.
+func (fc *FailoverCommittee) failover(call string, do func(committee.Committee) error) error {
+	var errs []string
+	for _, endpoint := range fc.ranked() {
+		latency, err := endpoint.call(do)
+		fc.mutex.Lock()
+		endpoint.health.observe(latency, err)
+		fc.rank()
+		fc.mutex.Unlock()
+		if err == nil {
+			zap.L().Info("Called gravity chain", zap.String("call", call), zap.String("endpoint", MaskURL(endpoint.health.URL)))
+			return nil
+		}
+		zap.L().Warn(
+			"Failed to call gravity chain, failing over",
+			zap.String("call", call),
+			zap.String("endpoint", MaskURL(endpoint.health.URL)),
+			zap.Error(err),
+		)
+		errs = append(errs, MaskURL(endpoint.health.URL)+": "+err.Error())
+	}
+	return errors.Errorf("all gravity chain endpoints failed to %s:\n\t%s", call, strings.Join(errs, "\n\t"))
+}
+
+// rank sorts the endpoints by health, which should be called with the lock held
+func (fc *FailoverCommittee) rank() {
+	sort.SliceStable(fc.endpoints, func(i, j int) bool {
+		return fc.endpoints[i].health.less(fc.endpoints[j].health)
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util_test
+
+// the tests of failover are in util_test, as test/fake imports util
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/iotex-election/committee"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-tools/test/fake"
+	"github.com/iotexproject/iotex-tools/util"
+)
+
+var endpointURLs = []string{"https://a.example.com", "https://b.example.com", "https://c.example.com"}
+
+// newFakeCommittees returns n fake committees, each serving the fixture of bookkeeper
+func newFakeCommittees(t *testing.T, n int) ([]*fake.Committee, []committee.Committee) {
+	t.Helper()
+	var (
+		fakes      []*fake.Committee
+		committees []committee.Committee
+	)
+	for i := 0; i < n; i++ {
+		c, err := fake.NewCommitteeFromFile("../bookkeeper/cmd/testdata/committee.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fakes = append(fakes, c)
+		committees = append(committees, c)
+	}
+	return fakes, committees
+}
+
+// ranks returns the urls of endpoints in the order of rank
+func ranks(fc *util.FailoverCommittee) []string {
+	var urls []string
+	for _, health := range fc.Health() {
+		urls = append(urls, health.URL)
+	}
+	return urls
+}
+
+func TestFailoverCommittee(t *testing.T) {
+	fakes, committees := newFakeCommittees(t, 3)
+	fc := util.NewFailoverCommitteeOf(endpointURLs, committees, 0)
+	// the top endpoint serves while it is healthy
+	if _, err := fc.FetchResultByHeight(100); err != nil {
+		t.Fatal(err)
+	}
+	if fakes[0].Fetches(100) != 1 || fakes[1].Fetches(100) != 0 {
+		t.Errorf("expecting the fetch from the top endpoint, but got %d and %d", fakes[0].Fetches(100), fakes[1].Fetches(100))
+	}
+	// fail over to the next endpoint if the top one errors
+	fakes[0].SetError(200, errors.New("connection refused"))
+	if _, err := fc.FetchResultByHeight(200); err != nil {
+		t.Fatal(err)
+	}
+	if fakes[0].Fetches(200) != 1 || fakes[1].Fetches(200) != 1 || fakes[2].Fetches(200) != 0 {
+		t.Errorf(
+			"expecting a failover from a to b, but got fetches %d, %d and %d",
+			fakes[0].Fetches(200),
+			fakes[1].Fetches(200),
+			fakes[2].Fetches(200),
+		)
+	}
+	// the failed endpoint is ranked after the healthy ones, and no longer tried first
+	expected := []string{endpointURLs[1], endpointURLs[2], endpointURLs[0]}
+	if urls := ranks(fc); strings.Join(urls, ",") != strings.Join(expected, ",") {
+		t.Errorf("expecting ranks %v, but got %v", expected, urls)
+	}
+	health := fc.Health()
+	if health[2].Requests != 2 || health[2].Errors != 1 || health[2].ErrorRate() != 0.5 {
+		t.Errorf("expecting 1 error in 2 requests of a, but got %+v", health[2])
+	}
+	fakes[0].SetError(200, nil)
+	if _, err := fc.FetchResultByHeight(200); err != nil {
+		t.Fatal(err)
+	}
+	if fakes[0].Fetches(200) != 1 || fakes[1].Fetches(200) != 2 {
+		t.Errorf("expecting the fetch from b, but got %d and %d", fakes[0].Fetches(200), fakes[1].Fetches(200))
+	}
+}
+
+func TestFailoverCommitteeAllFailed(t *testing.T) {
+	fakes, committees := newFakeCommittees(t, 3)
+	fc := util.NewFailoverCommitteeOf(endpointURLs, committees, 0)
+	for i, c := range fakes {
+		c.SetError(100, errors.Errorf("error of endpoint %d", i))
+	}
+	_, err := fc.FetchResultByHeight(100)
+	if err == nil {
+		t.Fatal("expecting error when all endpoints fail")
+	}
+	// the error aggregates the errors of every endpoint
+	for i, url := range endpointURLs {
+		if !strings.Contains(err.Error(), fmt.Sprintf("%s: error of endpoint %d", url, i)) {
+			t.Errorf("expecting error of %s in %v", url, err)
+		}
+	}
+	for i, c := range fakes {
+		if c.Fetches(100) != 1 {
+			t.Errorf("expecting endpoint %d to be tried once, but got %d", i, c.Fetches(100))
+		}
+	}
+}
+
+func TestFailoverCommitteeRateLimit(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	fakes, committees := newFakeCommittees(t, 1)
+	fc := util.NewFailoverCommitteeOf(endpointURLs[:1], committees, interval)
+	// concurrent fetches are spread out by the interval
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := fc.FetchResultByHeight(100)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 3*interval {
+		t.Errorf("expecting 4 fetches to take at least %s, but got %s", 3*interval, elapsed)
+	}
+	if fakes[0].Fetches(100) != 4 {
+		t.Errorf("expecting 4 fetches, but got %d", fakes[0].Fetches(100))
+	}
+	// no wait without a limit
+	_, committees = newFakeCommittees(t, 1)
+	fc = util.NewFailoverCommitteeOf(endpointURLs[:1], committees, 0)
+	start = time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := fc.FetchResultByHeight(100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed >= interval {
+		t.Errorf("expecting fetches without a limit to take less than %s, but got %s", interval, elapsed)
+	}
+}
+
+// brokenCommittee is a committee of which HeightByTime always fails and LatestHeight knows nothing
+type brokenCommittee struct {
+	committee.Committee
+}
+
+func (c brokenCommittee) HeightByTime(time.Time) (uint64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (c brokenCommittee) LatestHeight() uint64 { return 0 }
+
+func TestFailoverCommitteeHeight(t *testing.T) {
+	_, committees := newFakeCommittees(t, 2)
+	committees[0] = brokenCommittee{committees[0]}
+	var c committee.Committee = util.NewFailoverCommitteeOf(endpointURLs[:2], committees, 0)
+	if latest := c.LatestHeight(); latest != 400 {
+		t.Errorf("expecting latest height 400 of b, but got %d", latest)
+	}
+	height, err := c.HeightByTime(time.Date(2019, 5, 3, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 400 {
+		t.Errorf("expecting height 400, but got %d", height)
+	}
+	// the failed endpoint is ranked after the healthy one
+	expected := []string{endpointURLs[1], endpointURLs[0]}
+	if urls := ranks(c.(*util.FailoverCommittee)); strings.Join(urls, ",") != strings.Join(expected, ",") {
+		t.Errorf("expecting ranks %v, but got %v", expected, urls)
+	}
+	if _, err := c.HeightByTime(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expecting error when no endpoint knows the height")
+	}
+}
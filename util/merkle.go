@@ -0,0 +1,95 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// MerkleTree is a merkle tree over claim entries. A leaf is keccak256(index, account, amount) packed as
+// uint256, address and uint256, and a parent is keccak256 of its children in ascending order, so that a
+// claim contract could verify a proof without knowing the position of the leaf.
+type MerkleTree struct {
+	layers [][]common.Hash
+}
+
+// MerkleLeaf returns the leaf hash of a claim entry
+func MerkleLeaf(index uint64, account common.Address, amount *big.Int) common.Hash {
+	return crypto.Keccak256Hash(
+		common.LeftPadBytes(new(big.Int).SetUint64(index).Bytes(), 32),
+		account.Bytes(),
+		common.LeftPadBytes(amount.Bytes(), 32),
+	)
+}
+
+// NewMerkleTree builds a merkle tree of the leaves
+func NewMerkleTree(leaves []common.Hash) (*MerkleTree, error) {
+	if len(leaves) == 0 {
+		return nil, errors.New("no leaves to build merkle tree")
+	}
+	layers := [][]common.Hash{leaves}
+	for layer := leaves; len(layer) > 1; {
+		var next []common.Hash
+		for i := 0; i < len(layer); i += 2 {
+			if i+1 == len(layer) {
+				// the last node of an odd layer is promoted
+				next = append(next, layer[i])
+				continue
+			}
+			next = append(next, hashPair(layer[i], layer[i+1]))
+		}
+		layers = append(layers, next)
+		layer = next
+	}
+	return &MerkleTree{layers: layers}, nil
+}
+
+// Root returns the root of the tree
+func (t *MerkleTree) Root() common.Hash {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Proof returns the sibling hashes from the ith leaf up to the root
+func (t *MerkleTree) Proof(i int) ([]common.Hash, error) {
+	if i < 0 || i >= len(t.layers[0]) {
+		return nil, errors.Errorf("leaf %d is out of range", i)
+	}
+	var proof []common.Hash
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		sibling := i ^ 1
+		if sibling < len(layer) {
+			proof = append(proof, layer[sibling])
+		}
+		i /= 2
+	}
+	return proof, nil
+}
+
+// VerifyMerkleProof returns true if the leaf is in the tree of the root
+func VerifyMerkleProof(root common.Hash, leaf common.Hash, proof []common.Hash) bool {
+	computed := leaf
+	for _, sibling := range proof {
+		computed = hashPair(computed, sibling)
+	}
+	return computed == root
+}
+
+func hashPair(a common.Hash, b common.Hash) common.Hash {
+	if bytes.Compare(a.Bytes(), b.Bytes()) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a.Bytes(), b.Bytes())
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func merkleLeaves(n int) []common.Hash {
+	leaves := make([]common.Hash, n)
+	for i := range leaves {
+		account := common.BigToAddress(big.NewInt(int64(i + 1)))
+		leaves[i] = MerkleLeaf(uint64(i), account, big.NewInt(int64(1000*(i+1))))
+	}
+	return leaves
+}
+
+// sortedHash is keccak256 of the two hashes in ascending order
+func sortedHash(a common.Hash, b common.Hash) common.Hash {
+	if bytes.Compare(a.Bytes(), b.Bytes()) > 0 {
+		return crypto.Keccak256Hash(b.Bytes(), a.Bytes())
+	}
+	return crypto.Keccak256Hash(a.Bytes(), b.Bytes())
+}
+
+func TestMerkleLeaf(t *testing.T) {
+	account := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	packed := append(common.LeftPadBytes([]byte{7}, 32), account.Bytes()...)
+	packed = append(packed, common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)...)
+	if len(packed) != 84 {
+		t.Fatalf("expecting 84 packed bytes, but got %d", len(packed))
+	}
+	if leaf := MerkleLeaf(7, account, big.NewInt(1000)); leaf != crypto.Keccak256Hash(packed) {
+		t.Errorf("expecting leaf %s, but got %s", crypto.Keccak256Hash(packed).Hex(), leaf.Hex())
+	}
+	if MerkleLeaf(7, account, big.NewInt(1000)) == MerkleLeaf(8, account, big.NewInt(1000)) {
+		t.Error("expecting leaves of different indexes to differ")
+	}
+}
+
+func TestMerkleRoot(t *testing.T) {
+	l := merkleLeaves(5)
+	for _, test := range []struct {
+		leaves []common.Hash
+		root   common.Hash
+	}{
+		{l[:1], l[0]},
+		{l[:2], sortedHash(l[0], l[1])},
+		// the last node of an odd layer is promoted
+		{l[:3], sortedHash(sortedHash(l[0], l[1]), l[2])},
+		{l[:4], sortedHash(sortedHash(l[0], l[1]), sortedHash(l[2], l[3]))},
+		{l[:5], sortedHash(sortedHash(sortedHash(l[0], l[1]), sortedHash(l[2], l[3])), l[4])},
+	} {
+		tree, err := NewMerkleTree(test.leaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tree.Root() != test.root {
+			t.Errorf("expecting root %s of %d leaves, but got %s", test.root.Hex(), len(test.leaves), tree.Root().Hex())
+		}
+	}
+	// the root does not depend on the order of siblings
+	tree, err := NewMerkleTree([]common.Hash{l[1], l[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Root() != sortedHash(l[0], l[1]) {
+		t.Errorf("expecting root %s, but got %s", sortedHash(l[0], l[1]).Hex(), tree.Root().Hex())
+	}
+	if _, err := NewMerkleTree(nil); err == nil {
+		t.Error("expecting error of no leaves")
+	}
+}
+
+func TestMerkleProof(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 16, 17} {
+		leaves := merkleLeaves(n)
+		tree, err := NewMerkleTree(leaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, leaf := range leaves {
+			proof, err := tree.Proof(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyMerkleProof(tree.Root(), leaf, proof) {
+				t.Errorf("expecting proof of leaf %d of %d leaves to be valid", i, n)
+			}
+		}
+		if _, err := tree.Proof(n); err == nil {
+			t.Errorf("expecting error of leaf %d out of %d leaves", n, n)
+		}
+		if _, err := tree.Proof(-1); err == nil {
+			t.Error("expecting error of negative leaf")
+		}
+	}
+}
+
+func TestMerkleProofTampered(t *testing.T) {
+	leaves := merkleLeaves(7)
+	tree, err := NewMerkleTree(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewMerkleTree(merkleLeaves(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := tree.Proof(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := common.BigToAddress(big.NewInt(3))
+	for _, test := range []struct {
+		reason string
+		root   common.Hash
+		leaf   common.Hash
+		proof  []common.Hash
+	}{
+		{"more amount", tree.Root(), MerkleLeaf(2, account, big.NewInt(3001)), proof},
+		{"another index", tree.Root(), MerkleLeaf(3, account, big.NewInt(3000)), proof},
+		{"another account", tree.Root(), MerkleLeaf(2, common.BigToAddress(big.NewInt(4)), big.NewInt(3000)), proof},
+		{"proof of another leaf", tree.Root(), leaves[2], mustProof(t, tree, 3)},
+		{"flipped proof", tree.Root(), leaves[2], flipHash(proof, 1)},
+		{"truncated proof", tree.Root(), leaves[2], proof[:len(proof)-1]},
+		{"extra proof", tree.Root(), leaves[2], append(append([]common.Hash{}, proof...), leaves[0])},
+		{"no proof", tree.Root(), leaves[2], nil},
+		{"another root", other.Root(), leaves[2], proof},
+	} {
+		if VerifyMerkleProof(test.root, test.leaf, test.proof) {
+			t.Errorf("expecting proof of %s to be invalid", test.reason)
+		}
+	}
+}
+
+func mustProof(t *testing.T, tree *MerkleTree, i int) []common.Hash {
+	t.Helper()
+	proof, err := tree.Proof(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
+
+// flipHash returns a copy of the proof with the ith hash flipped
+func flipHash(proof []common.Hash, i int) []common.Hash {
+	flipped := append([]common.Hash{}, proof...)
+	flipped[i][0] ^= 0xff
+	return flipped
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/iotexproject/iotex-election/types"
+	"github.com/pkg/errors"
+)
+
+// snapshotMagic is the leading bytes of a snapshot, followed by the version
+const snapshotMagic = "IOTXSNAP"
+
+// snapshotVersion is the version of snapshot format
+const snapshotVersion = 1
+
+// ResultFetcher fetches the election result at a gravity chain height. It is implemented by committee.Committee
+// and SnapshotCommittee.
+type ResultFetcher interface {
+	FetchResultByHeight(height uint64) (*types.ElectionResult, error)
+}
+
+// SnapshotCommittee serves election results from the snapshots in a directory. If a snapshot does not exist, the
+// result is fetched from the fallback and archived, or an error is returned if there is no fallback.
+type SnapshotCommittee struct {
+	dir      string
+	fallback ResultFetcher
+}
+
+// NewSnapshotCommittee creates a committee of snapshots in dir, with an optional fallback
+func NewSnapshotCommittee(dir string, fallback ResultFetcher) (*SnapshotCommittee, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create snapshot directory %s", dir)
+	}
+	return &SnapshotCommittee{dir: dir, fallback: fallback}, nil
+}
+
+// FetchResultByHeight reads the result at height from its snapshot, and returns an error if the snapshot is of
+// another height
+func (c *SnapshotCommittee) FetchResultByHeight(height uint64) (*types.ElectionResult, error) {
+	filename := filepath.Join(c.dir, SnapshotFileName(height))
+	if _, err := os.Stat(filename); err == nil {
+		snapshotHeight, result, err := ReadSnapshot(filename)
+		if err != nil {
+			return nil, err
+		}
+		if snapshotHeight != height {
+			return nil, errors.Errorf("snapshot %s is of height %d rather than %d", filename, snapshotHeight, height)
+		}
+		return result, nil
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "failed to read snapshot %s", filename)
+	}
+	if c.fallback == nil {
+		return nil, errors.Errorf("no snapshot of height %d in %s", height, c.dir)
+	}
+	result, err := c.fallback.FetchResultByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	if err := WriteSnapshot(filename, height, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SnapshotFileName returns the file name of the snapshot at height
+func SnapshotFileName(height uint64) string {
+	return fmt.Sprintf("%d.snapshot.gz", height)
+}
+
+// WriteSnapshot writes the result at height to a gzip compressed file. The file is written to a temporary file in
+// the same directory first, and renamed, such that a partial snapshot is never left behind.
+func WriteSnapshot(filename string, height uint64, result *types.ElectionResult) error {
+	data, err := result.Serialize()
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize result of height %d", height)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	header := make([]byte, len(snapshotMagic)+1+8)
+	copy(header, snapshotMagic)
+	header[len(snapshotMagic)] = snapshotVersion
+	binary.BigEndian.PutUint64(header[len(snapshotMagic)+1:], height)
+	if _, err := zw.Write(header); err != nil {
+		return err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary file of snapshot %s", filename)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "failed to write snapshot %s", filename)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write snapshot %s", filename)
+	}
+	return errors.Wrapf(os.Rename(tmp.Name(), filename), "failed to rename snapshot %s", filename)
+}
+
+// ReadSnapshot reads the height and result from a snapshot file
+func ReadSnapshot(filename string) (uint64, *types.ElectionResult, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to open snapshot %s", filename)
+	}
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "invalid snapshot %s", filename)
+	}
+	data, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to decompress snapshot %s", filename)
+	}
+	headerLen := len(snapshotMagic) + 1 + 8
+	if len(data) < headerLen || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return 0, nil, errors.Errorf("%s is not a snapshot", filename)
+	}
+	if version := data[len(snapshotMagic)]; version != snapshotVersion {
+		return 0, nil, errors.Errorf("unsupported version %d of snapshot %s", version, filename)
+	}
+	height := binary.BigEndian.Uint64(data[len(snapshotMagic)+1 : headerLen])
+	result := &types.ElectionResult{}
+	if err := result.Deserialize(data[headerLen:]); err != nil {
+		return 0, nil, errors.Wrapf(err, "failed to deserialize snapshot %s", filename)
+	}
+	return height, result, nil
+}
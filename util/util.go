@@ -11,22 +11,55 @@
 package util
 
 import (
-	"io/ioutil"
-
 	"github.com/iotexproject/iotex-election/committee"
-	"github.com/pkg/errors"
-	yaml "gopkg.in/yaml.v2"
 )
 
-// NewCommitteeWithConfigFile creates a committee with config file
-func NewCommitteeWithConfigFile(filename string) (committee.Committee, error) {
-	data, err := ioutil.ReadFile(filename)
+// CommitteeOption customizes the committee created by NewCommitteeWithConfigFile and NewCommitteeWithConfig
+type CommitteeOption func(*committeeOptions)
+
+type committeeOptions struct {
+	overrides []func(*committee.Config)
+	rate      float64
+}
+
+// WithConfigOverride overrides the config after environment variables, e.g., with flags
+func WithConfigOverride(override func(*committee.Config)) CommitteeOption {
+	return func(options *committeeOptions) {
+		options.overrides = append(options.overrides, override)
+	}
+}
+
+// WithRateLimit limits the fetches from each gravity chain api to rate per second
+func WithRateLimit(rate float64) CommitteeOption {
+	return func(options *committeeOptions) {
+		options.rate = rate
+	}
+}
+
+// NewCommitteeWithConfigFile creates a committee with config file, overridden by environment variables and then
+// the overrides in options, which fails over between the gravity chain apis of config
+func NewCommitteeWithConfigFile(filename string, opts ...CommitteeOption) (committee.Committee, error) {
+	config, err := LoadCommitteeConfig(filename)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to load config file %s", filename)
+		return nil, err
 	}
-	var config committee.Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal config")
+	return NewCommitteeWithConfig(config, opts...)
+}
+
+// NewCommitteeWithConfig validates the config after the overrides in options, and creates a committee failing over
+// between its gravity chain apis
+func NewCommitteeWithConfig(config committee.Config, opts ...CommitteeOption) (committee.Committee, error) {
+	var options committeeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	for _, override := range options.overrides {
+		override(&config)
+	}
+	fc, err := NewFailoverCommittee(config, options.rate)
+	if err != nil {
+		// return a nil interface rather than a nil *FailoverCommittee
+		return nil, err
 	}
-	return committee.NewCommittee(nil, config)
+	return fc, nil
 }
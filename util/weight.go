@@ -0,0 +1,57 @@
+// Copyright (c) 2019 IoTeX
+// This program is free software: you can redistribute it and/or modify it under the terms of the
+// GNU General Public License as published by the Free Software Foundation, either version 3 of
+// the License, or (at your option) any later version.
+// This program is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See
+// the GNU General Public License for more details.
+// You should have received a copy of the GNU General Public License along with this program. If
+// not, see <http://www.gnu.org/licenses/>.
+
+package util
+
+import (
+	"math"
+	"math/big"
+	"time"
+)
+
+// secondsPerDay is the number of seconds in a day
+const secondsPerDay = 86400
+
+// RemainingTime returns the remaining lock time of a bucket at now. A bucket which has not started has no
+// remaining time. A decaying bucket counts down from the end of its duration, while a non-decaying bucket keeps
+// its full duration.
+func RemainingTime(startTime time.Time, duration time.Duration, decay bool, now time.Time) time.Duration {
+	if now.Before(startTime) {
+		return 0
+	}
+	if !decay {
+		return duration
+	}
+	if remaining := startTime.Add(duration).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// VoteWeight returns the weight of a bucket with the remaining time, which is
+// 1 + log_1.2(ceil(remaining days)) / 100, or 1 if no time remains
+func VoteWeight(remaining time.Duration) float64 {
+	weight := float64(1)
+	if seconds := remaining.Seconds(); seconds > 0 {
+		weight += math.Log(math.Ceil(seconds/secondsPerDay)) / math.Log(1.2) / 100
+	}
+	return weight
+}
+
+// WeightedVotes returns the weighted votes of a bucket at now. A bucket which has not started has no votes.
+func WeightedVotes(amount *big.Int, startTime time.Time, duration time.Duration, decay bool, now time.Time) *big.Int {
+	if now.Before(startTime) {
+		return big.NewInt(0)
+	}
+	weight := VoteWeight(RemainingTime(startTime, duration, decay, now))
+	weighted := new(big.Float).SetInt(amount)
+	votes, _ := weighted.Mul(weighted, big.NewFloat(weight)).Int(nil)
+	return votes
+}